@@ -5,8 +5,121 @@ const (
 	DEL    = "DEL"
 	EXPIRE = "EXPIRE"
 	KEYS   = "KEYS"
+	SCAN   = "SCAN"
 	SET    = "SET"
 	TTL    = "TTL"
+
+	PEXPIRE     = "PEXPIRE"
+	EXPIREAT    = "EXPIREAT"
+	PEXPIREAT   = "PEXPIREAT"
+	EXPIRETIME  = "EXPIRETIME"
+	PEXPIRETIME = "PEXPIRETIME"
+	PERSIST     = "PERSIST"
+	PTTL        = "PTTL"
+
+	SETEX  = "SETEX"
+	PSETEX = "PSETEX"
+	SETNX  = "SETNX"
+	GETSET = "GETSET"
+	GETDEL = "GETDEL"
+	GETEX  = "GETEX"
+
 	ZADD   = "ZADD"
 	ZRANGE = "ZRANGE"
+	ZSCAN  = "ZSCAN"
+
+	ZREVRANGE        = "ZREVRANGE"
+	ZRANGEBYSCORE    = "ZRANGEBYSCORE"
+	ZREVRANGEBYSCORE = "ZREVRANGEBYSCORE"
+	ZRANGEBYLEX      = "ZRANGEBYLEX"
+	ZREVRANGEBYLEX   = "ZREVRANGEBYLEX"
+	ZRANGESTORE      = "ZRANGESTORE"
+
+	ZSCORE           = "ZSCORE"
+	ZMSCORE          = "ZMSCORE"
+	ZREM             = "ZREM"
+	ZREMRANGEBYRANK  = "ZREMRANGEBYRANK"
+	ZREMRANGEBYSCORE = "ZREMRANGEBYSCORE"
+	ZREMRANGEBYLEX   = "ZREMRANGEBYLEX"
+	ZINCRBY          = "ZINCRBY"
+	ZCARD            = "ZCARD"
+	ZCOUNT           = "ZCOUNT"
+	ZLEXCOUNT        = "ZLEXCOUNT"
+
+	ZUNION      = "ZUNION"
+	ZINTER      = "ZINTER"
+	ZDIFF       = "ZDIFF"
+	ZUNIONSTORE = "ZUNIONSTORE"
+	ZINTERSTORE = "ZINTERSTORE"
+	ZDIFFSTORE  = "ZDIFFSTORE"
+	ZINTERCARD  = "ZINTERCARD"
+
+	ZPOPMIN     = "ZPOPMIN"
+	ZPOPMAX     = "ZPOPMAX"
+	BZPOPMIN    = "BZPOPMIN"
+	BZPOPMAX    = "BZPOPMAX"
+	ZMPOP       = "ZMPOP"
+	BZMPOP      = "BZMPOP"
+	ZRANDMEMBER = "ZRANDMEMBER"
+
+	LPUSH   = "LPUSH"
+	RPUSH   = "RPUSH"
+	LPOP    = "LPOP"
+	RPOP    = "RPOP"
+	LRANGE  = "LRANGE"
+	LLEN    = "LLEN"
+	LINDEX  = "LINDEX"
+	LSET    = "LSET"
+	LREM    = "LREM"
+	LTRIM   = "LTRIM"
+	LINSERT = "LINSERT"
+	BLPOP   = "BLPOP"
+	BRPOP   = "BRPOP"
+	LMOVE   = "LMOVE"
+	BLMOVE  = "BLMOVE"
+
+	HSET         = "HSET"
+	HSETNX       = "HSETNX"
+	HGET         = "HGET"
+	HMGET        = "HMGET"
+	HGETALL      = "HGETALL"
+	HDEL         = "HDEL"
+	HEXISTS      = "HEXISTS"
+	HLEN         = "HLEN"
+	HSTRLEN      = "HSTRLEN"
+	HKEYS        = "HKEYS"
+	HVALS        = "HVALS"
+	HINCRBY      = "HINCRBY"
+	HINCRBYFLOAT = "HINCRBYFLOAT"
+	HRANDFIELD   = "HRANDFIELD"
+	HSCAN        = "HSCAN"
+
+	ZRANK    = "ZRANK"
+	ZREVRANK = "ZREVRANK"
+	HELLO    = "HELLO"
+	COMMAND  = "COMMAND"
+	SHUTDOWN = "SHUTDOWN"
+)
+
+// command flags
+const (
+	FlagWrite    = "write"
+	FlagReadonly = "readonly"
+	FlagFast     = "fast"
+	FlagAdmin    = "admin"
+	FlagBlocking = "blocking"
+	// the keys can't be found from the first key, last key and step alone
+	FlagMovableKeys = "movablekeys"
+	FlagLoading     = "loading"
+	FlagStale       = "stale"
+)
+
+// acl categories of the data type a command works on
+const (
+	CategoryString     = "@string"
+	CategoryKeyspace   = "@keyspace"
+	CategorySortedSet  = "@sortedset"
+	CategoryList       = "@list"
+	CategoryHash       = "@hash"
+	CategoryConnection = "@connection"
 )
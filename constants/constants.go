@@ -1,4 +1,10 @@
 package constants
 
-// will read 1024 buffer size from terminal
-var ArgBufSize = 1024
+// largest bulk string accepted from a client, same default as redis
+var ProtoMaxBulkLen = 512 * 1024 * 1024
+
+// redis version reported to clients, the command semantics follow redis 7
+var Version = "7.0.0"
+
+// seconds running commands get to finish once a shutdown started
+var ShutdownTimeout = 10
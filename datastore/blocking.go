@@ -0,0 +1,112 @@
+package datastore
+
+import (
+	"slices"
+	"time"
+)
+
+// waiter is a client blocked on keys until a write lets it be served.
+type waiter struct {
+	keys []string
+	// serve tries to serve the client from key, it runs with the write lock
+	// held and reports whether it did
+	serve  func(key string) (bool, error)
+	served bool
+	wake   chan struct{} // closed once served
+}
+
+// block serves the client from the first key that can serve it, or parks it
+// on every key until a write makes one of them ready. Clients blocked on the
+// same key are served in the order they arrived. It gives up once timeout
+// passes, 0 waits forever, or done is closed, and reports whether the client
+// was served.
+func (ds *DataStore) block(keys []string, timeout time.Duration, done <-chan struct{}, serve func(key string) (bool, error)) (bool, error) {
+	ds.lock.Lock()
+	for _, key := range keys {
+		ds.expireIfNeeded(key)
+		served, err := serve(key)
+		if err != nil || served {
+			ds.lock.Unlock()
+			return served, err
+		}
+	}
+	w := &waiter{keys: keys, serve: serve, wake: make(chan struct{})}
+	for _, key := range keys {
+		ds.blocked[key] = append(ds.blocked[key], w)
+	}
+	ds.lock.Unlock()
+
+	var expired <-chan time.Time
+	if timeout > 0 {
+		timer := time.NewTimer(timeout)
+		defer timer.Stop()
+		expired = timer.C
+	}
+	select {
+	case <-w.wake:
+		return true, nil
+	case <-expired:
+	case <-done:
+	}
+	ds.lock.Lock()
+	defer ds.lock.Unlock()
+	// a write may have served the client while it was giving up
+	if w.served {
+		return true, nil
+	}
+	ds.unblock(w)
+	return false, nil
+}
+
+// unblock removes w from the keys it waits on. The caller holds the write
+// lock.
+func (ds *DataStore) unblock(w *waiter) {
+	for _, key := range w.keys {
+		waiters := slices.DeleteFunc(ds.blocked[key], func(other *waiter) bool { return other == w })
+		if len(waiters) == 0 {
+			delete(ds.blocked, key)
+		} else {
+			ds.blocked[key] = waiters
+		}
+	}
+}
+
+// signalKeyReady serves the clients blocked on key, oldest first, for as
+// long as key holds something for them. Writes call it once they added to a
+// key. Serving a client may write to other keys, like BLMOVE does, those are
+// queued and served once key is done rather than recursively, the way redis
+// handles its ready keys. The caller holds the write lock.
+func (ds *DataStore) signalKeyReady(key string) {
+	if _, ok := ds.blocked[key]; !ok {
+		return
+	}
+	ds.readyKeys = append(ds.readyKeys, key)
+	if len(ds.readyKeys) > 1 {
+		// an outer call is serving and gets to key later
+		return
+	}
+	for i := 0; i < len(ds.readyKeys); i++ {
+		ds.serveBlocked(ds.readyKeys[i])
+	}
+	ds.readyKeys = nil
+}
+
+// serveBlocked serves the clients blocked on key. The caller holds the write
+// lock.
+func (ds *DataStore) serveBlocked(key string) {
+	for _, w := range slices.Clone(ds.blocked[key]) {
+		if _, ok := ds.data.get(key); !ok {
+			return
+		}
+		// serving a client may have served others through another key
+		if w.served {
+			continue
+		}
+		if served, err := w.serve(key); err != nil || !served {
+			continue
+		}
+		w.served = true
+		ds.unblock(w)
+		close(w.wake)
+	}
+}
@@ -2,24 +2,49 @@ package datastore
 
 import (
 	"log"
-	"regexp"
+	"strings"
 	"sync"
-	"time"
 
-	"github.com/huandu/skiplist"
 	"github.com/saurabhy27/redis-database/errs"
 	"github.com/saurabhy27/redis-database/model"
 	"github.com/saurabhy27/redis-database/utils"
 )
 
 type DataStore struct {
-	lock       sync.RWMutex   // to avoid modifing values from multiple goroutines
-	data       map[string]any // key:value
-	expireData map[string]int // Key:expireEpoxTimestamp
+	lock       sync.RWMutex         // to avoid modifing values from multiple goroutines
+	data       *dict[any]           // key:value
+	expireData map[string]int64     // Key:expireEpoxTimestamp in milliseconds
+	blocked    map[string][]*waiter // key:clients blocked on it, oldest first
+	readyKeys  []string             // keys written to while serving blocked clients
 }
 
 func New() *DataStore {
-	return &DataStore{data: make(map[string]any), expireData: make(map[string]int)}
+	return &DataStore{data: newDict[any](), expireData: make(map[string]int64), blocked: make(map[string][]*waiter)}
+}
+
+// typeName returns the name of the type of value as reported by TYPE.
+func typeName(value any) string {
+	switch value.(type) {
+	case []byte:
+		return "string"
+	case *zset:
+		return "zset"
+	case *list:
+		return "list"
+	case *hash:
+		return "hash"
+	default:
+		return "none"
+	}
+}
+
+// deleteIfEmpty deletes key once the collection it holds has no elements
+// left, like redis never keeps empty collections. The caller holds the write
+// lock.
+func (ds *DataStore) deleteIfEmpty(key string, value interface{ len() int }) {
+	if value.len() == 0 {
+		ds.deleteKey(key)
+	}
 }
 
 func (ds *DataStore) Set(key string, value []byte) {
@@ -27,7 +52,9 @@ func (ds *DataStore) Set(key string, value []byte) {
 	log.Printf("Seting the value for key %s\n", key)
 	ds.lock.Lock()
 	defer ds.lock.Unlock()
-	ds.data[key] = value
+	// a new value discards the TTL of the old one
+	ds.deleteKey(key)
+	ds.data.set(key, value)
 }
 
 func (ds *DataStore) Get(key string) ([]byte, error) {
@@ -35,7 +62,7 @@ func (ds *DataStore) Get(key string) ([]byte, error) {
 	log.Printf("Fetching the value for key %s\n", key)
 	ds.lock.RLock()
 	defer ds.lock.RUnlock()
-	value, ok := ds.data[key]
+	value, ok := ds.lookup(key)
 	if !ok {
 		return nil, nil
 	}
@@ -46,123 +73,134 @@ func (ds *DataStore) Get(key string) ([]byte, error) {
 	return v, nil
 }
 
-func (ds *DataStore) Delete(key string) int {
-	log.Printf("Deleting the key %s\n", key)
+// SetWithOptions sets key to value the way SET does, checking the NX and XX
+// conditions and handling the TTL under the same lock. It returns the old
+// value when options.Get is set and whether the value was written.
+func (ds *DataStore) SetWithOptions(key string, value []byte, options model.SetOptions) ([]byte, bool, error) {
+	log.Printf("Seting the value for key %s with options %+v\n", key, options)
 	ds.lock.Lock()
 	defer ds.lock.Unlock()
-	_, ok := ds.data[key]
-	if !ok {
-		return 0
-	}
-	delete(ds.data, key)
-	delete(ds.expireData, key)
-	return 1
-}
-
-func (ds *DataStore) Keys(filter string) ([]string, error) {
-	log.Printf("Fetching all the keys with filter %s\n", filter)
-	ds.lock.RLock()
-	defer ds.lock.RUnlock()
-	var keys []string
-	for k := range ds.data {
-		keys = append(keys, k)
+	ds.expireIfNeeded(key)
+	current, exists := ds.data.get(key)
+	var old []byte
+	if options.Get && exists {
+		v, ok := current.([]byte)
+		if !ok {
+			return nil, false, errs.WrongType
+		}
+		old = v
 	}
-
-	var regMatchKeys []string
-	re, err := regexp.Compile(filter)
-	if err != nil {
-		return nil, err
+	if (options.NX && exists) || (options.XX && !exists) {
+		return old, false, nil
 	}
-	for _, key := range keys {
-		matches := re.FindAllString(key, -1)
-		if len(matches) != 0 {
-			regMatchKeys = append(regMatchKeys, key)
-		}
+	deadline, hasTTL := ds.expireData[key]
+	ds.deleteKey(key)
+	ds.data.set(key, value)
+	switch {
+	case options.Deadline != 0:
+		ds.expireData[key] = options.Deadline
+		// a deadline already in the past leaves nothing behind
+		ds.expireIfNeeded(key)
+	case options.KeepTTL && hasTTL:
+		ds.expireData[key] = deadline
 	}
-	return regMatchKeys, nil
+	return old, true, nil
 }
 
-func (ds *DataStore) expireInBackground(key string, seconds int) {
-	<-time.After(time.Duration(seconds) * time.Second)
-	ds.Delete(key)
+// GetDel returns the value of key and deletes it.
+func (ds *DataStore) GetDel(key string) ([]byte, error) {
+	log.Printf("Fetching and deleting the value for key %s\n", key)
+	ds.lock.Lock()
+	defer ds.lock.Unlock()
+	ds.expireIfNeeded(key)
+	value, ok := ds.data.get(key)
+	if !ok {
+		return nil, nil
+	}
+	v, ok := value.([]byte)
+	if !ok {
+		return nil, errs.WrongType
+	}
+	ds.deleteKey(key)
+	return v, nil
 }
 
-func (ds *DataStore) Expire(key string, seconds int) int {
-	log.Printf("Expiring the keys %s in %d seconds\n", key, seconds)
-	_, ok := ds.data[key]
+// GetEx returns the value of key and sets its deadline in unix milliseconds,
+// or removes its TTL when persist is set. A zero deadline leaves the TTL
+// untouched.
+func (ds *DataStore) GetEx(key string, deadline int64, persist bool) ([]byte, error) {
+	log.Printf("Fetching the value for key %s and updating its expire\n", key)
+	ds.lock.Lock()
+	defer ds.lock.Unlock()
+	ds.expireIfNeeded(key)
+	value, ok := ds.data.get(key)
 	if !ok {
-		return 0
+		return nil, nil
 	}
-	ds.expireData[key] = int(time.Now().Unix()) + seconds
-	go ds.expireInBackground(key, seconds)
-	return 1
+	v, ok := value.([]byte)
+	if !ok {
+		return nil, errs.WrongType
+	}
+	switch {
+	case persist:
+		delete(ds.expireData, key)
+	case deadline != 0:
+		ds.expireData[key] = deadline
+		ds.expireIfNeeded(key)
+	}
+	return v, nil
 }
 
-func (ds *DataStore) ZAdd(key string, sorted_set []model.SortedSetByte) (int, error) {
-	log.Printf("Adding the key %s score %v in sorted set\n", key, sorted_set)
+func (ds *DataStore) Delete(key string) int {
+	log.Printf("Deleting the key %s\n", key)
 	ds.lock.Lock()
 	defer ds.lock.Unlock()
-	value, ok := ds.data[key]
-	resp := 0
-	if ok {
-		sList, ok := value.(*skiplist.SkipList)
-		if !ok {
-			return 0, errs.WrongType
-		}
-		for _, set := range sorted_set {
-			sList.Set(set.Score, set.Member)
-			resp += 1
-		}
-		ds.data[key] = sList
-	} else {
-		skipList := skiplist.New(skiplist.Float64)
-		for _, set := range sorted_set {
-			skipList.Set(set.Score, set.Member)
-			resp += 1
-		}
-		ds.data[key] = skipList
+	ds.expireIfNeeded(key)
+	_, ok := ds.data.get(key)
+	if !ok {
+		return 0
 	}
-	return resp, nil
+	ds.deleteKey(key)
+	return 1
 }
 
-func (ds *DataStore) ZRange(key string, start int, stop int) ([]model.SortedSet, error) {
-	log.Printf("Retrieving the key %s from start index %d to stop index %d from sorted set\n", key, start, stop)
+// Keys returns every key matching the glob pattern.
+func (ds *DataStore) Keys(pattern string) ([]string, error) {
+	log.Printf("Fetching all the keys with pattern %s\n", pattern)
 	ds.lock.RLock()
 	defer ds.lock.RUnlock()
-	data := []model.SortedSet{}
-
-	value, ok := ds.data[key]
-	if ok {
-		sList, ok := value.(*skiplist.SkipList)
-		if !ok {
-			return nil, errs.WrongType
-		}
-		start, stop = utils.FormatArrayStartNEndIdx(start, stop, sList.Len())
-		if stop >= 0 && start <= stop {
-			s := sList.Front()
-			currentIndex := 0
-			for s != nil && currentIndex <= stop {
-				if currentIndex >= start {
-					data = append(data, model.SortedSet{Score: s.Score(), Member: string(s.Value.([]byte))})
-				}
-				currentIndex += 1
-				s = s.Next()
-			}
+	var keys []string
+	ds.data.each(func(k string, _ any) {
+		if !ds.isExpired(k) && utils.GlobMatch(pattern, k) {
+			keys = append(keys, k)
 		}
-	}
-	return data, nil
+	})
+	return keys, nil
 }
 
-func (ds *DataStore) Ttl(key string) int {
-	log.Printf("Retrieving the expire of key %s\n", key)
+// Scan returns a batch of keys and the cursor to pass to the next call, 0
+// once the whole keyspace was visited. Every key present from the first call
+// to the last one is returned at least once. A call visits buckets of the
+// keyspace until it collected about count keys, so the lock is only held for
+// a short time. Keys not matching the glob pattern or, unless empty, of
+// another type than typ are left out.
+func (ds *DataStore) Scan(cursor uint64, pattern string, count int, typ string) (uint64, []string, error) {
+	log.Printf("Scanning the keys from cursor %d with pattern %s\n", cursor, pattern)
 	ds.lock.RLock()
 	defer ds.lock.RUnlock()
-	expireEpoxTimestamp, ok := ds.expireData[key]
-	if ok {
-		diff := expireEpoxTimestamp - int(time.Now().Unix())
-		if diff > 0 {
-			return diff
+	keys := []string{}
+	cursor = ds.data.scanBatch(cursor, count, func(k string, value any) bool {
+		if ds.isExpired(k) {
+			return false
 		}
-	}
-	return -1
+		if typ != "" && !strings.EqualFold(typ, typeName(value)) {
+			return false
+		}
+		if !utils.GlobMatch(pattern, k) {
+			return false
+		}
+		keys = append(keys, k)
+		return true
+	})
+	return cursor, keys, nil
 }
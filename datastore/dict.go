@@ -0,0 +1,271 @@
+package datastore
+
+import (
+	"hash/maphash"
+	"math/bits"
+	"math/rand"
+)
+
+const (
+	// buckets of a new table
+	dictInitialSize = 4
+	// a table holding less than 1/dictMinFillRatio entries per bucket shrinks
+	dictMinFillRatio = 8
+	// empty buckets a single rehash step visits at most
+	dictRehashEmptyVisits = 10
+	// a scan batch visits at most count*dictScanMaxVisitsFactor buckets and
+	// entries
+	dictScanMaxVisitsFactor = 10
+)
+
+type dictEntry[V any] struct {
+	key   string
+	value V
+}
+
+// dict is a hash table with a power of two number of buckets, like the one
+// redis uses for its keyspace. Unlike a Go map it gives a scan cursor that
+// keeps working while entries are added and removed and the table resizes.
+// Resizing is incremental: while rehashing, entries are spread over two
+// tables and every write moves one bucket from the old table to the new one,
+// so no single command pays for rehashing the whole table.
+// Reads are safe under a read lock, writes need the write lock.
+type dict[V any] struct {
+	tables    [2][][]dictEntry[V]
+	rehashIdx int // next bucket of tables[0] to move, -1 when not rehashing
+	size      int
+	seed      maphash.Seed
+}
+
+func newDict[V any]() *dict[V] {
+	return &dict[V]{
+		tables:    [2][][]dictEntry[V]{make([][]dictEntry[V], dictInitialSize)},
+		rehashIdx: -1,
+		seed:      maphash.MakeSeed(),
+	}
+}
+
+func (d *dict[V]) len() int {
+	return d.size
+}
+
+func (d *dict[V]) isRehashing() bool {
+	return d.rehashIdx >= 0
+}
+
+func (d *dict[V]) hash(key string) uint64 {
+	return maphash.String(d.seed, key)
+}
+
+// find returns the table and bucket holding key and its position in the
+// bucket, or -1 as position when key is missing.
+func (d *dict[V]) find(key string) (int, uint64, int) {
+	h := d.hash(key)
+	for t := 0; t < 2; t++ {
+		table := d.tables[t]
+		if table == nil {
+			break
+		}
+		idx := h & uint64(len(table)-1)
+		for i, entry := range table[idx] {
+			if entry.key == key {
+				return t, idx, i
+			}
+		}
+		if !d.isRehashing() {
+			break
+		}
+	}
+	return 0, 0, -1
+}
+
+func (d *dict[V]) get(key string) (V, bool) {
+	t, idx, i := d.find(key)
+	if i < 0 {
+		var zero V
+		return zero, false
+	}
+	return d.tables[t][idx][i].value, true
+}
+
+// set adds or replaces key, it returns true when key is new.
+func (d *dict[V]) set(key string, value V) bool {
+	d.rehashStep()
+	if t, idx, i := d.find(key); i >= 0 {
+		d.tables[t][idx][i].value = value
+		return false
+	}
+	d.expandIfNeeded()
+	// while rehashing new entries only go to the new table
+	t := 0
+	if d.isRehashing() {
+		t = 1
+	}
+	table := d.tables[t]
+	idx := d.hash(key) & uint64(len(table)-1)
+	table[idx] = append(table[idx], dictEntry[V]{key: key, value: value})
+	d.size++
+	return true
+}
+
+// delete removes key, it returns true when key existed.
+func (d *dict[V]) delete(key string) bool {
+	d.rehashStep()
+	t, idx, i := d.find(key)
+	if i < 0 {
+		return false
+	}
+	bucket := d.tables[t][idx]
+	last := len(bucket) - 1
+	bucket[i] = bucket[last]
+	bucket[last] = dictEntry[V]{}
+	if last == 0 {
+		bucket = nil
+	} else {
+		bucket = bucket[:last]
+	}
+	d.tables[t][idx] = bucket
+	d.size--
+	d.shrinkIfNeeded()
+	return true
+}
+
+// each calls fn for every entry.
+func (d *dict[V]) each(fn func(key string, value V)) {
+	for _, table := range d.tables {
+		for _, bucket := range table {
+			for _, entry := range bucket {
+				fn(entry.key, entry.value)
+			}
+		}
+	}
+}
+
+// random returns a random entry, the dict must not be empty. Like redis it
+// picks a random non empty bucket then an entry of it, so entries sharing a
+// bucket are a bit less likely, which is fine for sampling.
+func (d *dict[V]) random() (string, V) {
+	small, large := d.tables[0], d.tables[1]
+	for {
+		// buckets of the old table below rehashIdx are already moved and empty
+		idx := rand.Intn(len(small) + len(large))
+		bucket := small
+		if idx >= len(small) {
+			idx, bucket = idx-len(small), large
+		}
+		if entries := bucket[idx]; len(entries) > 0 {
+			entry := entries[rand.Intn(len(entries))]
+			return entry.key, entry.value
+		}
+	}
+}
+
+func (d *dict[V]) expandIfNeeded() {
+	if !d.isRehashing() && d.size >= len(d.tables[0]) {
+		d.resize(len(d.tables[0]) * 2)
+	}
+}
+
+func (d *dict[V]) shrinkIfNeeded() {
+	size := len(d.tables[0])
+	if !d.isRehashing() && size > dictInitialSize && d.size*dictMinFillRatio < size {
+		d.resize(max(dictInitialSize, 1<<bits.Len(uint(d.size))))
+	}
+}
+
+// resize starts rehashing into a table of the given power of two size.
+func (d *dict[V]) resize(size int) {
+	d.tables[1] = make([][]dictEntry[V], size)
+	d.rehashIdx = 0
+}
+
+// rehashStep moves the next non empty bucket of the old table to the new
+// one and finishes the rehash once the old table is empty.
+func (d *dict[V]) rehashStep() {
+	if !d.isRehashing() {
+		return
+	}
+	old, table := d.tables[0], d.tables[1]
+	for visits := 0; d.rehashIdx < len(old) && visits < dictRehashEmptyVisits; visits++ {
+		bucket := old[d.rehashIdx]
+		old[d.rehashIdx] = nil
+		d.rehashIdx++
+		if bucket == nil {
+			continue
+		}
+		for _, entry := range bucket {
+			idx := d.hash(entry.key) & uint64(len(table)-1)
+			table[idx] = append(table[idx], entry)
+		}
+		break
+	}
+	if d.rehashIdx == len(old) {
+		d.tables[0], d.tables[1] = table, nil
+		d.rehashIdx = -1
+	}
+}
+
+// scan calls fn for the entries of the bucket cursor points to and returns
+// the cursor of the next bucket, 0 once every bucket was visited. This is the
+// reverse binary iteration of redis' dictScan: the cursor is incremented from
+// its high bits, so buckets that split or merge on a resize are visited in
+// an order that returns every entry present during the whole iteration at
+// least once, maybe more.
+func (d *dict[V]) scan(cursor uint64, fn func(key string, value V)) uint64 {
+	if d.size == 0 {
+		return 0
+	}
+	small, large := d.tables[0], d.tables[1]
+	if !d.isRehashing() {
+		mask := uint64(len(small) - 1)
+		emitBucket(small[cursor&mask], fn)
+		return nextCursor(cursor, mask)
+	}
+	if len(small) > len(large) {
+		small, large = large, small
+	}
+	smallMask, largeMask := uint64(len(small)-1), uint64(len(large)-1)
+	emitBucket(small[cursor&smallMask], fn)
+	// visit every bucket of the larger table that is an expansion of the
+	// bucket of the smaller one
+	for {
+		emitBucket(large[cursor&largeMask], fn)
+		cursor = nextCursor(cursor, largeMask)
+		if cursor&(smallMask^largeMask) == 0 {
+			return cursor
+		}
+	}
+}
+
+// scanBatch scans from cursor until fn accepted count entries, or a long run
+// of empty buckets and rejected entries was visited, and returns the cursor
+// to continue from.
+func (d *dict[V]) scanBatch(cursor uint64, count int, fn func(key string, value V) bool) uint64 {
+	accepted, visited := 0, 0
+	for {
+		cursor = d.scan(cursor, func(key string, value V) {
+			visited++
+			if fn(key, value) {
+				accepted++
+			}
+		})
+		visited++
+		if cursor == 0 || accepted >= count || visited >= count*dictScanMaxVisitsFactor {
+			return cursor
+		}
+	}
+}
+
+func emitBucket[V any](bucket []dictEntry[V], fn func(key string, value V)) {
+	for _, entry := range bucket {
+		fn(entry.key, entry.value)
+	}
+}
+
+// nextCursor increments the bits of cursor covered by mask in reverse order.
+func nextCursor(cursor uint64, mask uint64) uint64 {
+	cursor |= ^mask
+	cursor = bits.Reverse64(cursor)
+	cursor++
+	return bits.Reverse64(cursor)
+}
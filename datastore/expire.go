@@ -0,0 +1,179 @@
+package datastore
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/saurabhy27/redis-database/model"
+)
+
+const (
+	// how often the active expire cycle runs, like redis' default hz of 10
+	activeExpireInterval = 100 * time.Millisecond
+	// keys with a TTL sampled per round of the cycle
+	activeExpireKeysPerLoop = 20
+	// another round is run while more than this share of the sample expired
+	activeExpireStalePercent = 25
+	// upper bound of the time a single cycle holds the lock
+	activeExpireTimeLimit = 25 * time.Millisecond
+)
+
+// isExpired reports whether the deadline of key passed. The caller holds the
+// lock.
+func (ds *DataStore) isExpired(key string) bool {
+	deadline, ok := ds.expireData[key]
+	return ok && deadline <= time.Now().UnixMilli()
+}
+
+// lookup returns the value of key, an expired key is reported as missing.
+// The caller holds at least the read lock.
+func (ds *DataStore) lookup(key string) (any, bool) {
+	if ds.isExpired(key) {
+		return nil, false
+	}
+	value, ok := ds.data.get(key)
+	return value, ok
+}
+
+// expireIfNeeded deletes key once its deadline passed, so write commands
+// never see an expired value. The caller holds the write lock.
+func (ds *DataStore) expireIfNeeded(key string) {
+	if ds.isExpired(key) {
+		ds.deleteKey(key)
+	}
+}
+
+// deleteKey removes key with its TTL. The caller holds the write lock.
+func (ds *DataStore) deleteKey(key string) {
+	ds.data.delete(key)
+	delete(ds.expireData, key)
+}
+
+// Expire sets the TTL of key in seconds, it replaces any previous one.
+func (ds *DataStore) Expire(key string, seconds int) int {
+	return ds.PExpireAt(key, time.Now().UnixMilli()+int64(seconds)*1000, model.ExpireAlways)
+}
+
+// PExpireAt sets the deadline of key as a unix time in milliseconds, if the
+// current TTL of key meets condition. A deadline in the past deletes the key
+// right away. The key is otherwise deleted lazily on access or by the active
+// expire cycle. It returns 1 when the TTL was set and 0 when the key doesn't
+// exist or the condition isn't met.
+func (ds *DataStore) PExpireAt(key string, deadline int64, condition model.ExpireCondition) int {
+	log.Printf("Expiring the keys %s at %d\n", key, deadline)
+	ds.lock.Lock()
+	defer ds.lock.Unlock()
+	ds.expireIfNeeded(key)
+	if _, ok := ds.data.get(key); !ok {
+		return 0
+	}
+	current, hasTTL := ds.expireData[key]
+	// a key without TTL counts as never expiring for GT and LT
+	if condition&model.ExpireNX != 0 && hasTTL ||
+		condition&model.ExpireXX != 0 && !hasTTL ||
+		condition&model.ExpireGT != 0 && (!hasTTL || deadline <= current) ||
+		condition&model.ExpireLT != 0 && hasTTL && deadline >= current {
+		return 0
+	}
+	if deadline <= time.Now().UnixMilli() {
+		ds.deleteKey(key)
+		return 1
+	}
+	ds.expireData[key] = deadline
+	return 1
+}
+
+// Persist removes the TTL of key, it returns 1 if there was one.
+func (ds *DataStore) Persist(key string) int {
+	log.Printf("Removing the expire of key %s\n", key)
+	ds.lock.Lock()
+	defer ds.lock.Unlock()
+	ds.expireIfNeeded(key)
+	if _, ok := ds.expireData[key]; !ok {
+		return 0
+	}
+	delete(ds.expireData, key)
+	return 1
+}
+
+// Ttl returns the seconds left before key expires, -1 when it has no TTL and
+// -2 when it doesn't exist.
+func (ds *DataStore) Ttl(key string) int {
+	ttl := ds.PTtl(key)
+	if ttl < 0 {
+		return int(ttl)
+	}
+	return int((ttl + 500) / 1000)
+}
+
+// PTtl returns the milliseconds left before key expires, -1 when it has no
+// TTL and -2 when it doesn't exist.
+func (ds *DataStore) PTtl(key string) int64 {
+	deadline := ds.ExpireTime(key)
+	if deadline < 0 {
+		return deadline
+	}
+	return max(deadline-time.Now().UnixMilli(), 0)
+}
+
+// ExpireTime returns the deadline of key as a unix time in milliseconds, -1
+// when it has no TTL and -2 when it doesn't exist.
+func (ds *DataStore) ExpireTime(key string) int64 {
+	log.Printf("Retrieving the expire of key %s\n", key)
+	ds.lock.RLock()
+	defer ds.lock.RUnlock()
+	if _, ok := ds.lookup(key); !ok {
+		return -2
+	}
+	deadline, ok := ds.expireData[key]
+	if !ok {
+		return -1
+	}
+	return deadline
+}
+
+// RunActiveExpire reclaims expired keys nobody accesses anymore until ctx is
+// cancelled. Lazy expiration alone would keep them in memory forever.
+func (ds *DataStore) RunActiveExpire(ctx context.Context) {
+	ticker := time.NewTicker(activeExpireInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			ds.activeExpireCycle()
+		}
+	}
+}
+
+// activeExpireCycle samples keys with a TTL and deletes the expired ones,
+// the same way redis does. While many keys of the sample were expired there
+// are probably more, so sampling goes on until the time limit is reached.
+func (ds *DataStore) activeExpireCycle() {
+	ds.lock.Lock()
+	defer ds.lock.Unlock()
+	start := time.Now()
+	for {
+		sampled, expired := 0, 0
+		now := time.Now().UnixMilli()
+		// map iteration starts at a random position, which is our sample
+		for key, deadline := range ds.expireData {
+			if sampled == activeExpireKeysPerLoop {
+				break
+			}
+			sampled++
+			if deadline <= now {
+				ds.deleteKey(key)
+				expired++
+			}
+		}
+		if expired > 0 {
+			log.Printf("Active expire cycle deleted %d of %d sampled keys\n", expired, sampled)
+		}
+		if expired*100 <= sampled*activeExpireStalePercent || time.Since(start) > activeExpireTimeLimit {
+			return
+		}
+	}
+}
@@ -0,0 +1,346 @@
+package datastore
+
+import (
+	"log"
+	"math"
+	"math/rand"
+	"strconv"
+
+	"github.com/saurabhy27/redis-database/errs"
+	"github.com/saurabhy27/redis-database/model"
+	"github.com/saurabhy27/redis-database/utils"
+)
+
+// hash is the value of a hash key, a dict from field to value so every field
+// is read and written on its own and HSCAN gets the cursor of SCAN.
+type hash struct {
+	fields *dict[string]
+}
+
+func newHash() *hash {
+	return &hash{fields: newDict[string]()}
+}
+
+func (h *hash) len() int {
+	return h.fields.len()
+}
+
+// lookupHash returns the hash at key, nil when key doesn't exist. The caller
+// holds at least the read lock.
+func (ds *DataStore) lookupHash(key string) (*hash, error) {
+	value, ok := ds.lookup(key)
+	if !ok {
+		return nil, nil
+	}
+	h, ok := value.(*hash)
+	if !ok {
+		return nil, errs.WrongType
+	}
+	return h, nil
+}
+
+// hashForWrite returns the hash at key, created when key doesn't exist. The
+// caller holds the write lock.
+func (ds *DataStore) hashForWrite(key string) (*hash, error) {
+	ds.expireIfNeeded(key)
+	h, err := ds.lookupHash(key)
+	if err != nil || h != nil {
+		return h, err
+	}
+	h = newHash()
+	ds.data.set(key, h)
+	return h, nil
+}
+
+// HSet sets the fields of the hash at key and returns the number of fields
+// that were added rather than replaced. The hash is created when key doesn't
+// exist.
+func (ds *DataStore) HSet(key string, fields []model.FieldValue) (int, error) {
+	log.Printf("Setting %d fields of hash %s\n", len(fields), key)
+	ds.lock.Lock()
+	defer ds.lock.Unlock()
+	h, err := ds.hashForWrite(key)
+	if err != nil {
+		return 0, err
+	}
+	added := 0
+	for _, field := range fields {
+		if h.fields.set(field.Field, field.Value) {
+			added++
+		}
+	}
+	return added, nil
+}
+
+// HSetNX sets field of the hash at key only when it doesn't exist yet and
+// reports whether it did.
+func (ds *DataStore) HSetNX(key string, field string, value string) (bool, error) {
+	log.Printf("Setting the field %s of hash %s if it doesn't exist\n", field, key)
+	ds.lock.Lock()
+	defer ds.lock.Unlock()
+	h, err := ds.hashForWrite(key)
+	if err != nil {
+		return false, err
+	}
+	if _, ok := h.fields.get(field); ok {
+		return false, nil
+	}
+	h.fields.set(field, value)
+	return true, nil
+}
+
+// HGet returns the value of field in the hash at key, nil when the key or the
+// field doesn't exist.
+func (ds *DataStore) HGet(key string, field string) ([]byte, error) {
+	log.Printf("Fetching the field %s of hash %s\n", field, key)
+	ds.lock.RLock()
+	defer ds.lock.RUnlock()
+	h, err := ds.lookupHash(key)
+	if err != nil || h == nil {
+		return nil, err
+	}
+	value, ok := h.fields.get(field)
+	if !ok {
+		return nil, nil
+	}
+	return []byte(value), nil
+}
+
+// HMGet returns the values of fields in the hash at key, nil for the ones
+// that don't exist.
+func (ds *DataStore) HMGet(key string, fields []string) ([][]byte, error) {
+	log.Printf("Fetching the fields %v of hash %s\n", fields, key)
+	ds.lock.RLock()
+	defer ds.lock.RUnlock()
+	h, err := ds.lookupHash(key)
+	if err != nil {
+		return nil, err
+	}
+	values := make([][]byte, len(fields))
+	if h == nil {
+		return values, nil
+	}
+	for i, field := range fields {
+		if value, ok := h.fields.get(field); ok {
+			values[i] = []byte(value)
+		}
+	}
+	return values, nil
+}
+
+// HGetAll returns every field of the hash at key with its value.
+func (ds *DataStore) HGetAll(key string) ([]model.FieldValue, error) {
+	log.Printf("Fetching all the fields of hash %s\n", key)
+	ds.lock.RLock()
+	defer ds.lock.RUnlock()
+	fields := []model.FieldValue{}
+	h, err := ds.lookupHash(key)
+	if err != nil || h == nil {
+		return fields, err
+	}
+	h.fields.each(func(field string, value string) {
+		fields = append(fields, model.FieldValue{Field: field, Value: value})
+	})
+	return fields, nil
+}
+
+// HDel removes fields from the hash at key and returns the number removed.
+// The key is deleted once the hash is empty.
+func (ds *DataStore) HDel(key string, fields []string) (int, error) {
+	log.Printf("Deleting the fields %v of hash %s\n", fields, key)
+	ds.lock.Lock()
+	defer ds.lock.Unlock()
+	ds.expireIfNeeded(key)
+	h, err := ds.lookupHash(key)
+	if err != nil || h == nil {
+		return 0, err
+	}
+	removed := 0
+	for _, field := range fields {
+		if h.fields.delete(field) {
+			removed++
+		}
+	}
+	ds.deleteIfEmpty(key, h)
+	return removed, nil
+}
+
+// HExists reports whether field exists in the hash at key.
+func (ds *DataStore) HExists(key string, field string) (bool, error) {
+	log.Printf("Checking the field %s of hash %s\n", field, key)
+	ds.lock.RLock()
+	defer ds.lock.RUnlock()
+	h, err := ds.lookupHash(key)
+	if err != nil || h == nil {
+		return false, err
+	}
+	_, ok := h.fields.get(field)
+	return ok, nil
+}
+
+// HLen returns the number of fields of the hash at key.
+func (ds *DataStore) HLen(key string) (int, error) {
+	log.Printf("Retrieving the size of hash %s\n", key)
+	ds.lock.RLock()
+	defer ds.lock.RUnlock()
+	h, err := ds.lookupHash(key)
+	if err != nil || h == nil {
+		return 0, err
+	}
+	return h.len(), nil
+}
+
+// HStrLen returns the length of the value of field in the hash at key, 0
+// when the key or the field doesn't exist.
+func (ds *DataStore) HStrLen(key string, field string) (int, error) {
+	log.Printf("Retrieving the length of the field %s of hash %s\n", field, key)
+	ds.lock.RLock()
+	defer ds.lock.RUnlock()
+	h, err := ds.lookupHash(key)
+	if err != nil || h == nil {
+		return 0, err
+	}
+	value, _ := h.fields.get(field)
+	return len(value), nil
+}
+
+// HKeys returns the fields of the hash at key, or their values when values
+// is set.
+func (ds *DataStore) HKeys(key string, values bool) ([]string, error) {
+	log.Printf("Fetching the fields of hash %s\n", key)
+	ds.lock.RLock()
+	defer ds.lock.RUnlock()
+	result := []string{}
+	h, err := ds.lookupHash(key)
+	if err != nil || h == nil {
+		return result, err
+	}
+	h.fields.each(func(field string, value string) {
+		if values {
+			result = append(result, value)
+		} else {
+			result = append(result, field)
+		}
+	})
+	return result, nil
+}
+
+// HIncrBy adds increment to the integer value of field in the hash at key
+// and returns the new value. A missing field counts as 0.
+func (ds *DataStore) HIncrBy(key string, field string, increment int64) (int64, error) {
+	log.Printf("Incrementing the field %s of hash %s by %d\n", field, key, increment)
+	ds.lock.Lock()
+	defer ds.lock.Unlock()
+	h, err := ds.hashForWrite(key)
+	if err != nil {
+		return 0, err
+	}
+	var current int64
+	if value, ok := h.fields.get(field); ok {
+		if current, err = strconv.ParseInt(value, 10, 64); err != nil {
+			return 0, errs.HashValueNotInt
+		}
+	}
+	if increment > 0 && current > math.MaxInt64-increment || increment < 0 && current < math.MinInt64-increment {
+		// a hash created for the field isn't left behind empty
+		ds.deleteIfEmpty(key, h)
+		return 0, errs.IncrOverflow
+	}
+	current += increment
+	h.fields.set(field, strconv.FormatInt(current, 10))
+	return current, nil
+}
+
+// HIncrByFloat adds increment to the float value of field in the hash at key
+// and returns the new value. A missing field counts as 0.
+func (ds *DataStore) HIncrByFloat(key string, field string, increment float64) (float64, error) {
+	log.Printf("Incrementing the field %s of hash %s by %f\n", field, key, increment)
+	ds.lock.Lock()
+	defer ds.lock.Unlock()
+	h, err := ds.hashForWrite(key)
+	if err != nil {
+		return 0, err
+	}
+	var current float64
+	if value, ok := h.fields.get(field); ok {
+		if current, err = strconv.ParseFloat(value, 64); err != nil || math.IsNaN(current) {
+			return 0, errs.HashValueNotFloat
+		}
+	}
+	current += increment
+	if math.IsNaN(current) || math.IsInf(current, 0) {
+		ds.deleteIfEmpty(key, h)
+		return 0, errs.IncrNaNOrInfinity
+	}
+	h.fields.set(field, utils.FormatFloat(current))
+	return current, nil
+}
+
+// HRandField returns count random fields of the hash at key with their
+// values, all different unless count is negative. A positive count larger
+// than the hash returns the whole hash.
+func (ds *DataStore) HRandField(key string, count int) ([]model.FieldValue, error) {
+	log.Printf("Retrieving %d random fields of hash %s\n", count, key)
+	ds.lock.RLock()
+	defer ds.lock.RUnlock()
+	data := []model.FieldValue{}
+	h, err := ds.lookupHash(key)
+	if err != nil || h == nil || count == 0 {
+		return data, err
+	}
+	size := h.len()
+	switch {
+	case count < 0:
+		for i := 0; i < -count; i++ {
+			field, value := h.fields.random()
+			data = append(data, model.FieldValue{Field: field, Value: value})
+		}
+	case count*2 > size:
+		// most of the hash is returned, copying it is cheaper than drawing
+		fields := make([]model.FieldValue, 0, size)
+		h.fields.each(func(field string, value string) {
+			fields = append(fields, model.FieldValue{Field: field, Value: value})
+		})
+		if count >= size {
+			return fields, nil
+		}
+		for _, i := range rand.Perm(size)[:count] {
+			data = append(data, fields[i])
+		}
+	default:
+		// few fields out of many, draw until count are different
+		picked := map[string]bool{}
+		for len(data) < count {
+			if field, value := h.fields.random(); !picked[field] {
+				picked[field] = true
+				data = append(data, model.FieldValue{Field: field, Value: value})
+			}
+		}
+	}
+	return data, nil
+}
+
+// HScan returns a batch of about count fields of the hash at key matching
+// the glob pattern, with their values, and the cursor to pass to the next
+// call, 0 once every field was visited. It gives the same guarantees as Scan.
+func (ds *DataStore) HScan(key string, cursor uint64, pattern string, count int) (uint64, []model.FieldValue, error) {
+	log.Printf("Scanning the hash %s from cursor %d with pattern %s\n", key, cursor, pattern)
+	ds.lock.RLock()
+	defer ds.lock.RUnlock()
+	data := []model.FieldValue{}
+	h, err := ds.lookupHash(key)
+	if err != nil {
+		return 0, nil, err
+	}
+	if h == nil {
+		return 0, data, nil
+	}
+	cursor = h.fields.scanBatch(cursor, count, func(field string, value string) bool {
+		if !utils.GlobMatch(pattern, field) {
+			return false
+		}
+		data = append(data, model.FieldValue{Field: field, Value: value})
+		return true
+	})
+	return cursor, data, nil
+}
@@ -1,14 +1,69 @@
 package datastore
 
-import "github.com/saurabhy27/redis-database/model"
+import (
+	"time"
+
+	"github.com/saurabhy27/redis-database/model"
+)
 
 type DataStoreInterface interface {
 	Get(key string) ([]byte, error)
 	Delete(key string) int
 	Expire(key string, seconds int) int
-	Keys(filter string) ([]string, error)
-	Set(key string, value []byte)
+	PExpireAt(key string, deadline int64, condition model.ExpireCondition) int
+	Persist(key string) int
 	Ttl(key string) int
+	PTtl(key string) int64
+	ExpireTime(key string) int64
+	Keys(pattern string) ([]string, error)
+	Scan(cursor uint64, pattern string, count int, typ string) (uint64, []string, error)
+	Set(key string, value []byte)
+	SetWithOptions(key string, value []byte, options model.SetOptions) ([]byte, bool, error)
+	GetDel(key string) ([]byte, error)
+	GetEx(key string, deadline int64, persist bool) ([]byte, error)
 	ZAdd(key string, sorted_set []model.SortedSetByte) (int, error)
+	ZAddWithOptions(key string, sorted_set []model.SortedSetByte, options model.ZAddOptions) (int, *float64, error)
 	ZRange(key string, start int, stop int) ([]model.SortedSet, error)
+	ZRangeBy(key string, spec model.ZRangeSpec) ([]model.SortedSet, error)
+	ZRangeStore(dst string, src string, spec model.ZRangeSpec) (int, error)
+	ZRank(key string, member string, reverse bool) (int, float64, error)
+	ZMScore(key string, members []string) ([]*float64, error)
+	ZCard(key string) (int, error)
+	ZCount(key string, spec model.ZRangeSpec) (int, error)
+	ZIncrBy(key string, increment float64, member string) (float64, error)
+	ZRem(key string, members []string) (int, error)
+	ZRemRangeBy(key string, spec model.ZRangeSpec) (int, error)
+	ZSetOperation(op model.ZSetOp, keys []string, options model.ZSetOpOptions) ([]model.SortedSet, error)
+	ZSetOperationStore(dst string, op model.ZSetOp, keys []string, options model.ZSetOpOptions) (int, error)
+	ZInterCard(keys []string, limit int) (int, error)
+	ZPop(keys []string, max bool, count int) (string, []model.SortedSet, error)
+	BZPop(keys []string, max bool, count int, timeout time.Duration, done <-chan struct{}) (string, []model.SortedSet, error)
+	ZRandMember(key string, count int) ([]model.SortedSet, error)
+	ZScan(key string, cursor uint64, pattern string, count int) (uint64, []model.SortedSet, error)
+	Push(key string, elements []string, left bool) (int, error)
+	Pop(key string, left bool, count int) ([]string, error)
+	BPop(keys []string, left bool, timeout time.Duration, done <-chan struct{}) (string, []byte, error)
+	LMove(src string, dst string, srcLeft bool, dstLeft bool) ([]byte, error)
+	BLMove(src string, dst string, srcLeft bool, dstLeft bool, timeout time.Duration, done <-chan struct{}) ([]byte, error)
+	LLen(key string) (int, error)
+	LRange(key string, start int, stop int) ([]string, error)
+	LIndex(key string, index int) ([]byte, error)
+	LSet(key string, index int, element string) error
+	LRem(key string, count int, element string) (int, error)
+	LTrim(key string, start int, stop int) error
+	LInsert(key string, after bool, pivot string, element string) (int, error)
+	HSet(key string, fields []model.FieldValue) (int, error)
+	HSetNX(key string, field string, value string) (bool, error)
+	HGet(key string, field string) ([]byte, error)
+	HMGet(key string, fields []string) ([][]byte, error)
+	HGetAll(key string) ([]model.FieldValue, error)
+	HDel(key string, fields []string) (int, error)
+	HExists(key string, field string) (bool, error)
+	HLen(key string) (int, error)
+	HStrLen(key string, field string) (int, error)
+	HKeys(key string, values bool) ([]string, error)
+	HIncrBy(key string, field string, increment int64) (int64, error)
+	HIncrByFloat(key string, field string, increment float64) (float64, error)
+	HRandField(key string, count int) ([]model.FieldValue, error)
+	HScan(key string, cursor uint64, pattern string, count int) (uint64, []model.FieldValue, error)
 }
@@ -0,0 +1,537 @@
+package datastore
+
+import (
+	"log"
+	"slices"
+	"time"
+
+	"github.com/saurabhy27/redis-database/errs"
+	"github.com/saurabhy27/redis-database/utils"
+)
+
+// elements a chunk of a list holds at most
+const listChunkSize = 128
+
+type listNode struct {
+	prev, next *listNode
+	items      []string
+}
+
+// list is the value of a list key, a quicklist like the one of redis: a
+// doubly linked list of chunks holding up to listChunkSize elements each.
+// Pushes and pops at both ends only touch the first or last chunk, while
+// indexes walk the chunks from the closest end.
+type list struct {
+	head, tail *listNode
+	size       int
+}
+
+func newList() *list {
+	return &list{}
+}
+
+func (l *list) len() int {
+	return l.size
+}
+
+func (l *list) pushFront(value string) {
+	if l.head == nil || len(l.head.items) >= listChunkSize {
+		l.linkBefore(l.head, &listNode{items: make([]string, 0, 1)})
+	}
+	l.head.items = slices.Insert(l.head.items, 0, value)
+	l.size++
+}
+
+func (l *list) pushBack(value string) {
+	if l.tail == nil || len(l.tail.items) >= listChunkSize {
+		l.linkAfter(l.tail, &listNode{items: make([]string, 0, 1)})
+	}
+	l.tail.items = append(l.tail.items, value)
+	l.size++
+}
+
+// pop removes and returns up to count elements from the head, or the tail
+// when left isn't set, in the order they were popped.
+func (l *list) pop(left bool, count int) []string {
+	count = min(count, l.size)
+	elements := make([]string, 0, count)
+	for len(elements) < count {
+		if left {
+			elements = append(elements, l.head.items[0])
+			l.deleteAt(l.head, 0)
+		} else {
+			elements = append(elements, l.tail.items[len(l.tail.items)-1])
+			l.deleteAt(l.tail, len(l.tail.items)-1)
+		}
+	}
+	return elements
+}
+
+// locate returns the chunk holding the element at index and its position
+// in the chunk, index is in [0, len).
+func (l *list) locate(index int) (*listNode, int) {
+	if index < l.size/2 {
+		n := l.head
+		for index >= len(n.items) {
+			index -= len(n.items)
+			n = n.next
+		}
+		return n, index
+	}
+	index = l.size - 1 - index
+	n := l.tail
+	for index >= len(n.items) {
+		index -= len(n.items)
+		n = n.prev
+	}
+	return n, len(n.items) - 1 - index
+}
+
+// normalizeIndex turns a negative index into one counting from the head, it
+// returns false when index is out of range.
+func (l *list) normalizeIndex(index int) (int, bool) {
+	if index < 0 {
+		index += l.size
+	}
+	return index, index >= 0 && index < l.size
+}
+
+func (l *list) index(index int) (string, bool) {
+	index, ok := l.normalizeIndex(index)
+	if !ok {
+		return "", false
+	}
+	n, i := l.locate(index)
+	return n.items[i], true
+}
+
+func (l *list) set(index int, value string) bool {
+	index, ok := l.normalizeIndex(index)
+	if !ok {
+		return false
+	}
+	n, i := l.locate(index)
+	n.items[i] = value
+	return true
+}
+
+// rangeItems returns the elements from start to stop, both included, as
+// normalized by utils.FormatArrayStartNEndIdx.
+func (l *list) rangeItems(start, stop int) []string {
+	start, stop = utils.FormatArrayStartNEndIdx(start, stop, l.size)
+	if start > stop || start >= l.size {
+		return []string{}
+	}
+	elements := make([]string, 0, stop-start+1)
+	n, i := l.locate(start)
+	for len(elements) < stop-start+1 {
+		elements = append(elements, n.items[i:min(len(n.items), i+stop-start+1-len(elements))]...)
+		n, i = n.next, 0
+	}
+	return elements
+}
+
+// remove deletes the elements equal to value and returns their number.
+// A positive count removes the first count ones from the head, a negative
+// count the first ones from the tail and 0 all of them.
+func (l *list) remove(value string, count int) int {
+	limit := count
+	if limit < 0 {
+		limit = -limit
+	}
+	removed := 0
+	if count >= 0 {
+		for n := l.head; n != nil && (limit == 0 || removed < limit); {
+			next := n.next
+			for i := 0; i < len(n.items) && (limit == 0 || removed < limit); {
+				if n.items[i] == value {
+					l.deleteAt(n, i)
+					removed++
+				} else {
+					i++
+				}
+			}
+			n = next
+		}
+		return removed
+	}
+	for n := l.tail; n != nil && removed < limit; {
+		prev := n.prev
+		for i := len(n.items) - 1; i >= 0 && removed < limit; i-- {
+			if n.items[i] == value {
+				l.deleteAt(n, i)
+				removed++
+			}
+		}
+		n = prev
+	}
+	return removed
+}
+
+// trim keeps only the elements from start to stop, both included, as
+// normalized by utils.FormatArrayStartNEndIdx.
+func (l *list) trim(start, stop int) {
+	start, stop = utils.FormatArrayStartNEndIdx(start, stop, l.size)
+	if start > stop || start >= l.size {
+		start, stop = l.size, l.size
+	}
+	l.dropFront(start)
+	l.dropBack(l.size - (stop - start + 1))
+}
+
+// dropFront removes the first count elements, whole chunks at a time where
+// possible.
+func (l *list) dropFront(count int) {
+	for count > 0 && l.head != nil {
+		if n := l.head; len(n.items) <= count {
+			count -= len(n.items)
+			l.size -= len(n.items)
+			l.unlink(n)
+			continue
+		}
+		l.deleteAt(l.head, 0)
+		count--
+	}
+}
+
+// dropBack removes the last count elements.
+func (l *list) dropBack(count int) {
+	for count > 0 && l.tail != nil {
+		if n := l.tail; len(n.items) <= count {
+			count -= len(n.items)
+			l.size -= len(n.items)
+			l.unlink(n)
+			continue
+		}
+		l.deleteAt(l.tail, len(l.tail.items)-1)
+		count--
+	}
+}
+
+// insert adds value before or after the first element equal to pivot from
+// the head, it returns false when pivot isn't in the list.
+func (l *list) insert(pivot string, value string, after bool) bool {
+	for n := l.head; n != nil; n = n.next {
+		i := slices.Index(n.items, pivot)
+		if i < 0 {
+			continue
+		}
+		if after {
+			i++
+		}
+		n.items = slices.Insert(n.items, i, value)
+		l.size++
+		// a full chunk is split in two halves
+		if len(n.items) > listChunkSize {
+			half := len(n.items) / 2
+			l.linkAfter(n, &listNode{items: slices.Clone(n.items[half:])})
+			clear(n.items[half:])
+			n.items = n.items[:half]
+		}
+		return true
+	}
+	return false
+}
+
+// deleteAt removes the element at position i of chunk n and unlinks the
+// chunk once it is empty.
+func (l *list) deleteAt(n *listNode, i int) {
+	last := len(n.items) - 1
+	copy(n.items[i:], n.items[i+1:])
+	n.items[last] = ""
+	n.items = n.items[:last]
+	l.size--
+	if len(n.items) == 0 {
+		l.unlink(n)
+	}
+}
+
+func (l *list) linkBefore(at *listNode, n *listNode) {
+	if at == nil {
+		l.head, l.tail = n, n
+		return
+	}
+	n.prev, n.next = at.prev, at
+	if at.prev == nil {
+		l.head = n
+	} else {
+		at.prev.next = n
+	}
+	at.prev = n
+}
+
+func (l *list) linkAfter(at *listNode, n *listNode) {
+	if at == nil {
+		l.head, l.tail = n, n
+		return
+	}
+	n.prev, n.next = at, at.next
+	if at.next == nil {
+		l.tail = n
+	} else {
+		at.next.prev = n
+	}
+	at.next = n
+}
+
+func (l *list) unlink(n *listNode) {
+	if n.prev == nil {
+		l.head = n.next
+	} else {
+		n.prev.next = n.next
+	}
+	if n.next == nil {
+		l.tail = n.prev
+	} else {
+		n.next.prev = n.prev
+	}
+	n.prev, n.next = nil, nil
+}
+
+// lookupList returns the list at key, nil when key doesn't exist. The caller
+// holds at least the read lock.
+func (ds *DataStore) lookupList(key string) (*list, error) {
+	value, ok := ds.lookup(key)
+	if !ok {
+		return nil, nil
+	}
+	l, ok := value.(*list)
+	if !ok {
+		return nil, errs.WrongType
+	}
+	return l, nil
+}
+
+// Push adds elements one after the other to the head of the list at key, or
+// to its tail when left isn't set, and returns the length of the list. The
+// list is created when key doesn't exist.
+func (ds *DataStore) Push(key string, elements []string, left bool) (int, error) {
+	log.Printf("Pushing %v to list %s\n", elements, key)
+	ds.lock.Lock()
+	defer ds.lock.Unlock()
+	ds.expireIfNeeded(key)
+	l, err := ds.lookupList(key)
+	if err != nil {
+		return 0, err
+	}
+	if l == nil {
+		l = newList()
+		ds.data.set(key, l)
+	}
+	for _, element := range elements {
+		if left {
+			l.pushFront(element)
+		} else {
+			l.pushBack(element)
+		}
+	}
+	length := l.len()
+	ds.signalKeyReady(key)
+	return length, nil
+}
+
+// Pop removes and returns up to count elements from the head of the list at
+// key, or from its tail when left isn't set. It returns nil when key doesn't
+// exist. The key is deleted once the list is empty.
+func (ds *DataStore) Pop(key string, left bool, count int) ([]string, error) {
+	log.Printf("Popping %d elements from list %s\n", count, key)
+	ds.lock.Lock()
+	defer ds.lock.Unlock()
+	ds.expireIfNeeded(key)
+	l, err := ds.lookupList(key)
+	if err != nil || l == nil {
+		return nil, err
+	}
+	elements := l.pop(left, count)
+	ds.deleteIfEmpty(key, l)
+	return elements, nil
+}
+
+// BPop pops an element from the head, or the tail when left isn't set, of
+// the first list among keys that exists, blocking until one of them gets
+// elements, timeout passes or done is closed. It returns the key popped from,
+// empty when it gave up.
+func (ds *DataStore) BPop(keys []string, left bool, timeout time.Duration, done <-chan struct{}) (string, []byte, error) {
+	log.Printf("Popping an element from lists %v, waiting up to %v\n", keys, timeout)
+	var popped string
+	var element []byte
+	_, err := ds.block(keys, timeout, done, func(key string) (bool, error) {
+		l, err := ds.lookupList(key)
+		if err != nil || l == nil {
+			return false, err
+		}
+		popped, element = key, []byte(l.pop(left, 1)[0])
+		ds.deleteIfEmpty(key, l)
+		return true, nil
+	})
+	return popped, element, err
+}
+
+// LMove atomically pops an element from the head of the list at src, or its
+// tail when srcLeft isn't set, and pushes it to the head or the tail of the
+// list at dst. It returns the element, nil when src doesn't exist. src and
+// dst may be the same list, which rotates it.
+func (ds *DataStore) LMove(src string, dst string, srcLeft bool, dstLeft bool) ([]byte, error) {
+	log.Printf("Moving an element from list %s to list %s\n", src, dst)
+	ds.lock.Lock()
+	defer ds.lock.Unlock()
+	ds.expireIfNeeded(src)
+	return ds.lmove(src, dst, srcLeft, dstLeft)
+}
+
+// BLMove is LMove blocking until the list at src gets elements, timeout
+// passes or done is closed. It returns nil when it gave up.
+func (ds *DataStore) BLMove(src string, dst string, srcLeft bool, dstLeft bool, timeout time.Duration, done <-chan struct{}) ([]byte, error) {
+	log.Printf("Moving an element from list %s to list %s, waiting up to %v\n", src, dst, timeout)
+	var element []byte
+	_, err := ds.block([]string{src}, timeout, done, func(key string) (bool, error) {
+		moved, err := ds.lmove(src, dst, srcLeft, dstLeft)
+		if err != nil || moved == nil {
+			return false, err
+		}
+		element = moved
+		return true, nil
+	})
+	return element, err
+}
+
+// lmove moves an element from src to dst, it returns nil when src doesn't
+// exist. The caller holds the write lock.
+func (ds *DataStore) lmove(src string, dst string, srcLeft bool, dstLeft bool) ([]byte, error) {
+	from, err := ds.lookupList(src)
+	if err != nil || from == nil {
+		return nil, err
+	}
+	ds.expireIfNeeded(dst)
+	to, err := ds.lookupList(dst)
+	if err != nil {
+		return nil, err
+	}
+	if to == nil {
+		to = newList()
+		ds.data.set(dst, to)
+	}
+	element := from.pop(srcLeft, 1)[0]
+	if dstLeft {
+		to.pushFront(element)
+	} else {
+		to.pushBack(element)
+	}
+	ds.deleteIfEmpty(src, from)
+	ds.signalKeyReady(dst)
+	return []byte(element), nil
+}
+
+// LLen returns the length of the list at key, 0 when key doesn't exist.
+func (ds *DataStore) LLen(key string) (int, error) {
+	log.Printf("Retrieving the length of list %s\n", key)
+	ds.lock.RLock()
+	defer ds.lock.RUnlock()
+	l, err := ds.lookupList(key)
+	if err != nil || l == nil {
+		return 0, err
+	}
+	return l.len(), nil
+}
+
+// LRange returns the elements of the list at key from index start to stop.
+func (ds *DataStore) LRange(key string, start int, stop int) ([]string, error) {
+	log.Printf("Retrieving the range %d %d of list %s\n", start, stop, key)
+	ds.lock.RLock()
+	defer ds.lock.RUnlock()
+	l, err := ds.lookupList(key)
+	if err != nil {
+		return nil, err
+	}
+	if l == nil {
+		return []string{}, nil
+	}
+	return l.rangeItems(start, stop), nil
+}
+
+// LIndex returns the element at index of the list at key, nil when key
+// doesn't exist or index is out of range.
+func (ds *DataStore) LIndex(key string, index int) ([]byte, error) {
+	log.Printf("Retrieving the element %d of list %s\n", index, key)
+	ds.lock.RLock()
+	defer ds.lock.RUnlock()
+	l, err := ds.lookupList(key)
+	if err != nil || l == nil {
+		return nil, err
+	}
+	element, ok := l.index(index)
+	if !ok {
+		return nil, nil
+	}
+	return []byte(element), nil
+}
+
+// LSet replaces the element at index of the list at key.
+func (ds *DataStore) LSet(key string, index int, element string) error {
+	log.Printf("Setting the element %d of list %s\n", index, key)
+	ds.lock.Lock()
+	defer ds.lock.Unlock()
+	ds.expireIfNeeded(key)
+	l, err := ds.lookupList(key)
+	if err != nil {
+		return err
+	}
+	if l == nil {
+		return errs.NoSuchKey
+	}
+	if !l.set(index, element) {
+		return errs.IndexOutOfRange
+	}
+	return nil
+}
+
+// LRem removes the elements equal to element from the list at key, see
+// list.remove for count, and returns their number. The key is deleted once
+// the list is empty.
+func (ds *DataStore) LRem(key string, count int, element string) (int, error) {
+	log.Printf("Removing %d elements %s from list %s\n", count, element, key)
+	ds.lock.Lock()
+	defer ds.lock.Unlock()
+	ds.expireIfNeeded(key)
+	l, err := ds.lookupList(key)
+	if err != nil || l == nil {
+		return 0, err
+	}
+	removed := l.remove(element, count)
+	ds.deleteIfEmpty(key, l)
+	return removed, nil
+}
+
+// LTrim keeps only the elements of the list at key from index start to
+// stop. The key is deleted once the list is empty.
+func (ds *DataStore) LTrim(key string, start int, stop int) error {
+	log.Printf("Trimming list %s to %d %d\n", key, start, stop)
+	ds.lock.Lock()
+	defer ds.lock.Unlock()
+	ds.expireIfNeeded(key)
+	l, err := ds.lookupList(key)
+	if err != nil || l == nil {
+		return err
+	}
+	l.trim(start, stop)
+	ds.deleteIfEmpty(key, l)
+	return nil
+}
+
+// LInsert adds element before or after pivot in the list at key and returns
+// the length of the list, -1 when pivot isn't found and 0 when key doesn't
+// exist.
+func (ds *DataStore) LInsert(key string, after bool, pivot string, element string) (int, error) {
+	log.Printf("Inserting %s next to %s in list %s\n", element, pivot, key)
+	ds.lock.Lock()
+	defer ds.lock.Unlock()
+	ds.expireIfNeeded(key)
+	l, err := ds.lookupList(key)
+	if err != nil || l == nil {
+		return 0, err
+	}
+	if !l.insert(pivot, element, after) {
+		return -1, nil
+	}
+	return l.len(), nil
+}
@@ -0,0 +1,322 @@
+package datastore
+
+import (
+	"math/rand"
+
+	"github.com/saurabhy27/redis-database/model"
+)
+
+const (
+	// enough levels for 2^64 members with skiplistP
+	skiplistMaxLevel = 32
+	// chance of a node to reach the next level
+	skiplistP = 0.25
+)
+
+type skiplistLevel struct {
+	forward *skiplistNode
+	span    int // nodes skipped to reach forward, used to compute ranks
+}
+
+type skiplistNode struct {
+	member   string
+	score    float64
+	backward *skiplistNode
+	levels   []skiplistLevel
+}
+
+// next returns the node following n, nil at the end of the list.
+func (n *skiplistNode) next() *skiplistNode {
+	return n.levels[0].forward
+}
+
+// skiplist orders the members of a sorted set by score, then member. It is
+// the zskiplist of redis: every link also counts the nodes it skips, so
+// finding the rank of a node or the node at a rank takes O(log n) like a
+// lookup does. Ranks start at 0.
+type skiplist struct {
+	header *skiplistNode
+	tail   *skiplistNode
+	length int
+	level  int
+}
+
+func newSkiplist() *skiplist {
+	return &skiplist{
+		header: &skiplistNode{levels: make([]skiplistLevel, skiplistMaxLevel)},
+		level:  1,
+	}
+}
+
+func randomLevel() int {
+	level := 1
+	for level < skiplistMaxLevel && rand.Float64() < skiplistP {
+		level++
+	}
+	return level
+}
+
+// compare returns -1, 0 or 1 when n sorts before, at or after score and
+// member.
+func (n *skiplistNode) compare(score float64, member string) int {
+	switch {
+	case n.score < score:
+		return -1
+	case n.score > score:
+		return 1
+	case n.member < member:
+		return -1
+	case n.member > member:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// insert adds member, which must not be in the list yet.
+func (zsl *skiplist) insert(score float64, member string) *skiplistNode {
+	var update [skiplistMaxLevel]*skiplistNode
+	var rank [skiplistMaxLevel]int
+	x := zsl.header
+	for i := zsl.level - 1; i >= 0; i-- {
+		if i < zsl.level-1 {
+			rank[i] = rank[i+1]
+		}
+		for x.levels[i].forward != nil && x.levels[i].forward.compare(score, member) < 0 {
+			rank[i] += x.levels[i].span
+			x = x.levels[i].forward
+		}
+		update[i] = x
+	}
+	level := randomLevel()
+	if level > zsl.level {
+		for i := zsl.level; i < level; i++ {
+			update[i] = zsl.header
+			update[i].levels[i].span = zsl.length
+		}
+		zsl.level = level
+	}
+	x = &skiplistNode{member: member, score: score, levels: make([]skiplistLevel, level)}
+	for i := 0; i < level; i++ {
+		x.levels[i].forward = update[i].levels[i].forward
+		update[i].levels[i].forward = x
+		// rank[0]-rank[i] nodes lie between update[i] and x
+		x.levels[i].span = update[i].levels[i].span - (rank[0] - rank[i])
+		update[i].levels[i].span = rank[0] - rank[i] + 1
+	}
+	// the levels above x skip one more node now
+	for i := level; i < zsl.level; i++ {
+		update[i].levels[i].span++
+	}
+	if update[0] != zsl.header {
+		x.backward = update[0]
+	}
+	if x.levels[0].forward != nil {
+		x.levels[0].forward.backward = x
+	} else {
+		zsl.tail = x
+	}
+	zsl.length++
+	return x
+}
+
+// delete removes member with the given score, it returns false when it
+// isn't in the list.
+func (zsl *skiplist) delete(score float64, member string) bool {
+	var update [skiplistMaxLevel]*skiplistNode
+	x := zsl.header
+	for i := zsl.level - 1; i >= 0; i-- {
+		for x.levels[i].forward != nil && x.levels[i].forward.compare(score, member) < 0 {
+			x = x.levels[i].forward
+		}
+		update[i] = x
+	}
+	x = x.levels[0].forward
+	if x == nil || x.score != score || x.member != member {
+		return false
+	}
+	zsl.deleteNode(x, update[:zsl.level])
+	return true
+}
+
+// deleteNode unlinks x, update holds the last node before x on every level.
+func (zsl *skiplist) deleteNode(x *skiplistNode, update []*skiplistNode) {
+	for i, prev := range update {
+		if prev.levels[i].forward == x {
+			prev.levels[i].span += x.levels[i].span - 1
+			prev.levels[i].forward = x.levels[i].forward
+		} else {
+			prev.levels[i].span--
+		}
+	}
+	if x.levels[0].forward != nil {
+		x.levels[0].forward.backward = x.backward
+	} else {
+		zsl.tail = x.backward
+	}
+	for zsl.level > 1 && zsl.header.levels[zsl.level-1].forward == nil {
+		zsl.level--
+	}
+	zsl.length--
+}
+
+// rank returns the rank of member with the given score, -1 when it isn't in
+// the list.
+func (zsl *skiplist) rank(score float64, member string) int {
+	rank := 0
+	x := zsl.header
+	for i := zsl.level - 1; i >= 0; i-- {
+		for x.levels[i].forward != nil && x.levels[i].forward.compare(score, member) <= 0 {
+			rank += x.levels[i].span
+			x = x.levels[i].forward
+		}
+		if x != zsl.header && x.score == score && x.member == member {
+			return rank - 1
+		}
+	}
+	return -1
+}
+
+// byRank returns the node at rank, nil when rank is out of range.
+func (zsl *skiplist) byRank(rank int) *skiplistNode {
+	if rank < 0 || rank >= zsl.length {
+		return nil
+	}
+	// the header sits at rank -1
+	traversed := -1
+	x := zsl.header
+	for i := zsl.level - 1; i >= 0; i-- {
+		for x.levels[i].forward != nil && traversed+x.levels[i].span <= rank {
+			traversed += x.levels[i].span
+			x = x.levels[i].forward
+		}
+		if traversed == rank {
+			return x
+		}
+	}
+	return nil
+}
+
+func scoreGteMin(score float64, r *model.ScoreRange) bool {
+	if r.MinExclusive {
+		return score > r.Min
+	}
+	return score >= r.Min
+}
+
+func scoreLteMax(score float64, r *model.ScoreRange) bool {
+	if r.MaxExclusive {
+		return score < r.Max
+	}
+	return score <= r.Max
+}
+
+func lexGteMin(member string, r *model.LexRange) bool {
+	switch {
+	case r.MinInfinite:
+		return true
+	case r.MinExclusive:
+		return member > r.Min
+	default:
+		return member >= r.Min
+	}
+}
+
+func lexLteMax(member string, r *model.LexRange) bool {
+	switch {
+	case r.MaxInfinite:
+		return true
+	case r.MaxExclusive:
+		return member < r.Max
+	default:
+		return member <= r.Max
+	}
+}
+
+// isInScoreRange reports whether some part of the list is in range.
+func (zsl *skiplist) isInScoreRange(r *model.ScoreRange) bool {
+	if r.Min > r.Max || r.Min == r.Max && (r.MinExclusive || r.MaxExclusive) {
+		return false
+	}
+	return zsl.tail != nil && scoreGteMin(zsl.tail.score, r) && scoreLteMax(zsl.header.next().score, r)
+}
+
+// firstInScoreRange returns the first node in range, nil when there is none.
+func (zsl *skiplist) firstInScoreRange(r *model.ScoreRange) *skiplistNode {
+	if !zsl.isInScoreRange(r) {
+		return nil
+	}
+	x := zsl.header
+	for i := zsl.level - 1; i >= 0; i-- {
+		for x.levels[i].forward != nil && !scoreGteMin(x.levels[i].forward.score, r) {
+			x = x.levels[i].forward
+		}
+	}
+	// the list reaches into the range, so there is a next node
+	x = x.next()
+	if !scoreLteMax(x.score, r) {
+		return nil
+	}
+	return x
+}
+
+// lastInScoreRange returns the last node in range, nil when there is none.
+func (zsl *skiplist) lastInScoreRange(r *model.ScoreRange) *skiplistNode {
+	if !zsl.isInScoreRange(r) {
+		return nil
+	}
+	x := zsl.header
+	for i := zsl.level - 1; i >= 0; i-- {
+		for x.levels[i].forward != nil && scoreLteMax(x.levels[i].forward.score, r) {
+			x = x.levels[i].forward
+		}
+	}
+	if !scoreGteMin(x.score, r) {
+		return nil
+	}
+	return x
+}
+
+// isInLexRange reports whether some part of the list is in range. Like in
+// redis lex ranges assume every member has the same score.
+func (zsl *skiplist) isInLexRange(r *model.LexRange) bool {
+	if !r.MinInfinite && !r.MaxInfinite && (r.Min > r.Max || r.Min == r.Max && (r.MinExclusive || r.MaxExclusive)) {
+		return false
+	}
+	return zsl.tail != nil && lexGteMin(zsl.tail.member, r) && lexLteMax(zsl.header.next().member, r)
+}
+
+// firstInLexRange returns the first node in range, nil when there is none.
+func (zsl *skiplist) firstInLexRange(r *model.LexRange) *skiplistNode {
+	if !zsl.isInLexRange(r) {
+		return nil
+	}
+	x := zsl.header
+	for i := zsl.level - 1; i >= 0; i-- {
+		for x.levels[i].forward != nil && !lexGteMin(x.levels[i].forward.member, r) {
+			x = x.levels[i].forward
+		}
+	}
+	x = x.next()
+	if !lexLteMax(x.member, r) {
+		return nil
+	}
+	return x
+}
+
+// lastInLexRange returns the last node in range, nil when there is none.
+func (zsl *skiplist) lastInLexRange(r *model.LexRange) *skiplistNode {
+	if !zsl.isInLexRange(r) {
+		return nil
+	}
+	x := zsl.header
+	for i := zsl.level - 1; i >= 0; i-- {
+		for x.levels[i].forward != nil && lexLteMax(x.levels[i].forward.member, r) {
+			x = x.levels[i].forward
+		}
+	}
+	if !lexGteMin(x.member, r) {
+		return nil
+	}
+	return x
+}
@@ -0,0 +1,526 @@
+package datastore
+
+import (
+	"log"
+	"math"
+	"math/rand"
+	"time"
+
+	"github.com/saurabhy27/redis-database/errs"
+	"github.com/saurabhy27/redis-database/model"
+	"github.com/saurabhy27/redis-database/utils"
+)
+
+// zset is the value of a sorted set key, like redis it keeps every member
+// twice: a dict from member to score for lookups and a skiplist ordered by
+// score and member for ranges and ranks.
+type zset struct {
+	scores  *dict[float64]
+	ordered *skiplist
+}
+
+func newZset() *zset {
+	return &zset{scores: newDict[float64](), ordered: newSkiplist()}
+}
+
+func (zs *zset) len() int {
+	return zs.scores.len()
+}
+
+// add sets the score of member, it returns true when member is new.
+func (zs *zset) add(member string, score float64) bool {
+	current, exists := zs.scores.get(member)
+	if exists {
+		if current == score {
+			return false
+		}
+		zs.ordered.delete(current, member)
+	}
+	zs.scores.set(member, score)
+	zs.ordered.insert(score, member)
+	return !exists
+}
+
+// remove deletes member, it returns false when member isn't in the set.
+func (zs *zset) remove(member string) bool {
+	score, ok := zs.scores.get(member)
+	if !ok {
+		return false
+	}
+	zs.scores.delete(member)
+	zs.ordered.delete(score, member)
+	return true
+}
+
+// pop removes and returns up to count members with the lowest scores, or the
+// highest ones when max is set.
+func (zs *zset) pop(max bool, count int) []model.SortedSet {
+	data := []model.SortedSet{}
+	for len(data) < count && zs.len() > 0 {
+		x := zs.ordered.header.next()
+		if max {
+			x = zs.ordered.tail
+		}
+		data = append(data, model.SortedSet{Score: x.score, Member: x.member})
+		zs.remove(x.member)
+	}
+	return data
+}
+
+// rank returns the rank of member counted from the lowest score, or from the
+// highest one when reverse is set, and its score. The rank is -1 when member
+// isn't in the set.
+func (zs *zset) rank(member string, reverse bool) (int, float64) {
+	score, ok := zs.scores.get(member)
+	if !ok {
+		return -1, 0
+	}
+	rank := zs.ordered.rank(score, member)
+	if reverse {
+		rank = zs.len() - 1 - rank
+	}
+	return rank, score
+}
+
+// rangeBySpec returns the members spec selects, in the order spec walks
+// the set.
+func (zs *zset) rangeBySpec(spec model.ZRangeSpec) []model.SortedSet {
+	data := []model.SortedSet{}
+	var x *skiplistNode
+	var inRange func(x *skiplistNode) bool
+	switch spec.By {
+	case model.ZRangeByRank:
+		start, stop := utils.FormatArrayStartNEndIdx(spec.Start, spec.Stop, zs.len())
+		if start > stop {
+			return data
+		}
+		if spec.Rev {
+			x = zs.ordered.byRank(zs.len() - 1 - start)
+		} else {
+			x = zs.ordered.byRank(start)
+		}
+		spec.Offset, spec.Count = 0, stop-start+1
+		inRange = func(*skiplistNode) bool { return true }
+	case model.ZRangeByScore:
+		if spec.Rev {
+			x = zs.ordered.lastInScoreRange(&spec.Score)
+			inRange = func(x *skiplistNode) bool { return scoreGteMin(x.score, &spec.Score) }
+		} else {
+			x = zs.ordered.firstInScoreRange(&spec.Score)
+			inRange = func(x *skiplistNode) bool { return scoreLteMax(x.score, &spec.Score) }
+		}
+	case model.ZRangeByLex:
+		if spec.Rev {
+			x = zs.ordered.lastInLexRange(&spec.Lex)
+			inRange = func(x *skiplistNode) bool { return lexGteMin(x.member, &spec.Lex) }
+		} else {
+			x = zs.ordered.firstInLexRange(&spec.Lex)
+			inRange = func(x *skiplistNode) bool { return lexLteMax(x.member, &spec.Lex) }
+		}
+	}
+	if spec.Offset < 0 {
+		return data
+	}
+	step := (*skiplistNode).next
+	if spec.Rev {
+		step = func(x *skiplistNode) *skiplistNode { return x.backward }
+	}
+	for ; x != nil && spec.Offset > 0 && inRange(x); spec.Offset-- {
+		x = step(x)
+	}
+	for ; x != nil && spec.Count != 0 && inRange(x); x = step(x) {
+		data = append(data, model.SortedSet{Score: x.score, Member: x.member})
+		spec.Count--
+	}
+	return data
+}
+
+// count returns the number of members in the score or lex range of spec.
+// The ranks of the first and last member in range give it without walking
+// the range.
+func (zs *zset) count(spec model.ZRangeSpec) int {
+	var first, last *skiplistNode
+	if spec.By == model.ZRangeByLex {
+		first, last = zs.ordered.firstInLexRange(&spec.Lex), zs.ordered.lastInLexRange(&spec.Lex)
+	} else {
+		first, last = zs.ordered.firstInScoreRange(&spec.Score), zs.ordered.lastInScoreRange(&spec.Score)
+	}
+	if first == nil || last == nil {
+		return 0
+	}
+	return zs.ordered.rank(last.score, last.member) - zs.ordered.rank(first.score, first.member) + 1
+}
+
+// lookupZset returns the sorted set at key, nil when key doesn't exist. The
+// caller holds at least the read lock.
+func (ds *DataStore) lookupZset(key string) (*zset, error) {
+	value, ok := ds.lookup(key)
+	if !ok {
+		return nil, nil
+	}
+	zs, ok := value.(*zset)
+	if !ok {
+		return nil, errs.WrongType
+	}
+	return zs, nil
+}
+
+// ZAdd sets the scores of the members of the sorted set at key, creating it
+// when missing. It returns the number of members that were new.
+func (ds *DataStore) ZAdd(key string, sorted_set []model.SortedSetByte) (int, error) {
+	added, _, err := ds.ZAddWithOptions(key, sorted_set, model.ZAddOptions{})
+	return added, err
+}
+
+// ZAddWithOptions sets the scores of the members of the sorted set at key the
+// way ZADD does, checking the flags of options for every member. It returns
+// the number of members added, plus the ones whose score changed with CH.
+// With INCR it also returns the new score, nil when a flag prevented the
+// update.
+func (ds *DataStore) ZAddWithOptions(key string, sorted_set []model.SortedSetByte, options model.ZAddOptions) (int, *float64, error) {
+	log.Printf("Adding the key %s score %v in sorted set with options %+v\n", key, sorted_set, options)
+	ds.lock.Lock()
+	defer ds.lock.Unlock()
+	ds.expireIfNeeded(key)
+	zs, err := ds.lookupZset(key)
+	if err != nil {
+		return 0, nil, err
+	}
+	created := zs == nil
+	if created {
+		zs = newZset()
+	}
+	count := 0
+	var newScore *float64
+	for _, set := range sorted_set {
+		member, score := string(set.Member), set.Score
+		current, exists := zs.scores.get(member)
+		if !exists {
+			if options.XX {
+				continue
+			}
+			zs.add(member, score)
+			count++
+		} else {
+			if options.NX {
+				continue
+			}
+			if options.Incr {
+				score += current
+				if math.IsNaN(score) {
+					return 0, nil, errs.ScoreNaN
+				}
+			}
+			if options.GT && score <= current || options.LT && score >= current {
+				continue
+			}
+			if score != current {
+				zs.add(member, score)
+				if options.CH {
+					count++
+				}
+			}
+		}
+		if options.Incr {
+			newScore = &score
+		}
+	}
+	// a new set is only stored once a member made it in
+	if created && zs.len() > 0 {
+		ds.data.set(key, zs)
+	}
+	ds.signalKeyReady(key)
+	return count, newScore, nil
+}
+
+// ZRange returns the members of the sorted set at key from rank start to
+// stop.
+func (ds *DataStore) ZRange(key string, start int, stop int) ([]model.SortedSet, error) {
+	return ds.ZRangeBy(key, model.ZRangeSpec{By: model.ZRangeByRank, Start: start, Stop: stop})
+}
+
+// ZRangeBy returns the members of the sorted set at key that spec selects.
+func (ds *DataStore) ZRangeBy(key string, spec model.ZRangeSpec) ([]model.SortedSet, error) {
+	log.Printf("Retrieving the range %+v of sorted set %s\n", spec, key)
+	ds.lock.RLock()
+	defer ds.lock.RUnlock()
+	zs, err := ds.lookupZset(key)
+	if err != nil || zs == nil {
+		return []model.SortedSet{}, err
+	}
+	return zs.rangeBySpec(spec), nil
+}
+
+// ZRangeStore stores the members of the sorted set at src that spec selects
+// in dst, replacing any value there, and returns their number. An empty
+// range deletes dst.
+func (ds *DataStore) ZRangeStore(dst string, src string, spec model.ZRangeSpec) (int, error) {
+	log.Printf("Storing the range %+v of sorted set %s in %s\n", spec, src, dst)
+	ds.lock.Lock()
+	defer ds.lock.Unlock()
+	ds.expireIfNeeded(src)
+	zs, err := ds.lookupZset(src)
+	if err != nil {
+		return 0, err
+	}
+	var data []model.SortedSet
+	if zs != nil {
+		data = zs.rangeBySpec(spec)
+	}
+	ds.deleteKey(dst)
+	if len(data) == 0 {
+		return 0, nil
+	}
+	stored := newZset()
+	for _, member := range data {
+		stored.add(member.Member, member.Score)
+	}
+	ds.data.set(dst, stored)
+	ds.signalKeyReady(dst)
+	return len(data), nil
+}
+
+// ZRank returns the rank of member in the sorted set at key, counted from
+// the lowest score or from the highest one when reverse is set, and its
+// score. The rank is -1 when the key or the member doesn't exist.
+func (ds *DataStore) ZRank(key string, member string, reverse bool) (int, float64, error) {
+	log.Printf("Retrieving the rank of member %s in sorted set %s\n", member, key)
+	ds.lock.RLock()
+	defer ds.lock.RUnlock()
+	zs, err := ds.lookupZset(key)
+	if err != nil || zs == nil {
+		return -1, 0, err
+	}
+	rank, score := zs.rank(member, reverse)
+	return rank, score, nil
+}
+
+// ZMScore returns the score of every member of the sorted set at key, nil
+// for the members not in the set.
+func (ds *DataStore) ZMScore(key string, members []string) ([]*float64, error) {
+	log.Printf("Retrieving the score of members %v in sorted set %s\n", members, key)
+	ds.lock.RLock()
+	defer ds.lock.RUnlock()
+	zs, err := ds.lookupZset(key)
+	if err != nil {
+		return nil, err
+	}
+	scores := make([]*float64, len(members))
+	if zs == nil {
+		return scores, nil
+	}
+	for i, member := range members {
+		if score, ok := zs.scores.get(member); ok {
+			scores[i] = &score
+		}
+	}
+	return scores, nil
+}
+
+// ZCard returns the number of members of the sorted set at key.
+func (ds *DataStore) ZCard(key string) (int, error) {
+	log.Printf("Retrieving the size of sorted set %s\n", key)
+	ds.lock.RLock()
+	defer ds.lock.RUnlock()
+	zs, err := ds.lookupZset(key)
+	if err != nil || zs == nil {
+		return 0, err
+	}
+	return zs.len(), nil
+}
+
+// ZCount returns the number of members of the sorted set at key in the score
+// or lex range of spec.
+func (ds *DataStore) ZCount(key string, spec model.ZRangeSpec) (int, error) {
+	log.Printf("Counting the range %+v of sorted set %s\n", spec, key)
+	ds.lock.RLock()
+	defer ds.lock.RUnlock()
+	zs, err := ds.lookupZset(key)
+	if err != nil || zs == nil {
+		return 0, err
+	}
+	return zs.count(spec), nil
+}
+
+// ZIncrBy adds increment to the score of member in the sorted set at key,
+// adding the member or creating the set when missing, and returns the new
+// score.
+func (ds *DataStore) ZIncrBy(key string, increment float64, member string) (float64, error) {
+	log.Printf("Incrementing the score of member %s in sorted set %s by %f\n", member, key, increment)
+	ds.lock.Lock()
+	defer ds.lock.Unlock()
+	ds.expireIfNeeded(key)
+	zs, err := ds.lookupZset(key)
+	if err != nil {
+		return 0, err
+	}
+	score := increment
+	if zs != nil {
+		current, _ := zs.scores.get(member)
+		score += current
+	}
+	// +inf plus -inf
+	if math.IsNaN(score) {
+		return 0, errs.ScoreNaN
+	}
+	if zs == nil {
+		zs = newZset()
+		ds.data.set(key, zs)
+	}
+	zs.add(member, score)
+	ds.signalKeyReady(key)
+	return score, nil
+}
+
+// ZRem removes members from the sorted set at key and returns how many were
+// in it. The key is deleted once the set is empty.
+func (ds *DataStore) ZRem(key string, members []string) (int, error) {
+	log.Printf("Removing the members %v from sorted set %s\n", members, key)
+	ds.lock.Lock()
+	defer ds.lock.Unlock()
+	ds.expireIfNeeded(key)
+	zs, err := ds.lookupZset(key)
+	if err != nil || zs == nil {
+		return 0, err
+	}
+	removed := 0
+	for _, member := range members {
+		if zs.remove(member) {
+			removed++
+		}
+	}
+	ds.deleteIfEmpty(key, zs)
+	return removed, nil
+}
+
+// ZRemRangeBy removes the members of the sorted set at key that spec selects
+// and returns their number. The key is deleted once the set is empty.
+func (ds *DataStore) ZRemRangeBy(key string, spec model.ZRangeSpec) (int, error) {
+	log.Printf("Removing the range %+v from sorted set %s\n", spec, key)
+	ds.lock.Lock()
+	defer ds.lock.Unlock()
+	ds.expireIfNeeded(key)
+	zs, err := ds.lookupZset(key)
+	if err != nil || zs == nil {
+		return 0, err
+	}
+	data := zs.rangeBySpec(spec)
+	for _, member := range data {
+		zs.remove(member.Member)
+	}
+	ds.deleteIfEmpty(key, zs)
+	return len(data), nil
+}
+
+// ZPop pops up to count members with the lowest scores, or the highest ones
+// when max is set, from the first sorted set among keys that exists. It
+// returns the key popped from, empty when none exists. The key is deleted
+// once the set is empty.
+func (ds *DataStore) ZPop(keys []string, max bool, count int) (string, []model.SortedSet, error) {
+	log.Printf("Popping %d members from sorted sets %v\n", count, keys)
+	ds.lock.Lock()
+	defer ds.lock.Unlock()
+	for _, key := range keys {
+		ds.expireIfNeeded(key)
+		data, err := ds.zpop(key, max, count)
+		if err != nil || data != nil {
+			return key, data, err
+		}
+	}
+	return "", nil, nil
+}
+
+// BZPop is ZPop blocking until one of the sorted sets at keys gets members,
+// timeout passes or done is closed. It returns an empty key when it gave up.
+func (ds *DataStore) BZPop(keys []string, max bool, count int, timeout time.Duration, done <-chan struct{}) (string, []model.SortedSet, error) {
+	log.Printf("Popping %d members from sorted sets %v, waiting up to %v\n", count, keys, timeout)
+	var popped string
+	var data []model.SortedSet
+	_, err := ds.block(keys, timeout, done, func(key string) (bool, error) {
+		members, err := ds.zpop(key, max, count)
+		if err != nil || members == nil {
+			return false, err
+		}
+		popped, data = key, members
+		return true, nil
+	})
+	return popped, data, err
+}
+
+// zpop pops from the sorted set at key, it returns nil when key doesn't
+// exist. The caller holds the write lock.
+func (ds *DataStore) zpop(key string, max bool, count int) ([]model.SortedSet, error) {
+	zs, err := ds.lookupZset(key)
+	if err != nil || zs == nil {
+		return nil, err
+	}
+	data := zs.pop(max, count)
+	ds.deleteIfEmpty(key, zs)
+	return data, nil
+}
+
+// ZRandMember returns count random members of the sorted set at key, all
+// different unless count is negative. A positive count larger than the set
+// returns the whole set.
+func (ds *DataStore) ZRandMember(key string, count int) ([]model.SortedSet, error) {
+	log.Printf("Retrieving %d random members of sorted set %s\n", count, key)
+	ds.lock.RLock()
+	defer ds.lock.RUnlock()
+	data := []model.SortedSet{}
+	zs, err := ds.lookupZset(key)
+	if err != nil || zs == nil {
+		return data, err
+	}
+	size := zs.len()
+	var ranks []int
+	switch {
+	case count < 0:
+		for i := 0; i < -count; i++ {
+			ranks = append(ranks, rand.Intn(size))
+		}
+	case count >= size:
+		return zs.rangeBySpec(model.ZRangeSpec{By: model.ZRangeByRank, Start: 0, Stop: -1}), nil
+	case count*2 > size:
+		ranks = rand.Perm(size)[:count]
+	default:
+		// few members out of many, draw until count are different
+		picked := map[int]bool{}
+		for len(ranks) < count {
+			if rank := rand.Intn(size); !picked[rank] {
+				picked[rank] = true
+				ranks = append(ranks, rank)
+			}
+		}
+	}
+	for _, rank := range ranks {
+		x := zs.ordered.byRank(rank)
+		data = append(data, model.SortedSet{Score: x.score, Member: x.member})
+	}
+	return data, nil
+}
+
+// ZScan returns a batch of about count members of the sorted set at key
+// matching the glob pattern and the cursor to pass to the next call, 0 once
+// every member was visited. It walks the member dict, so it gives the same
+// guarantees as Scan.
+func (ds *DataStore) ZScan(key string, cursor uint64, pattern string, count int) (uint64, []model.SortedSet, error) {
+	log.Printf("Scanning the sorted set %s from cursor %d with pattern %s\n", key, cursor, pattern)
+	ds.lock.RLock()
+	defer ds.lock.RUnlock()
+	data := []model.SortedSet{}
+	zs, err := ds.lookupZset(key)
+	if err != nil {
+		return 0, nil, err
+	}
+	if zs == nil {
+		return 0, data, nil
+	}
+	cursor = zs.scores.scanBatch(cursor, count, func(member string, score float64) bool {
+		if !utils.GlobMatch(pattern, member) {
+			return false
+		}
+		data = append(data, model.SortedSet{Score: score, Member: member})
+		return true
+	})
+	return cursor, data, nil
+}
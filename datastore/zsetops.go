@@ -0,0 +1,187 @@
+package datastore
+
+import (
+	"log"
+	"math"
+	"slices"
+	"sort"
+
+	"github.com/saurabhy27/redis-database/model"
+)
+
+// zsetOperation computes the union, intersection or difference of the sorted
+// sets at keys into a new set. Missing keys count as empty sets. The caller
+// holds at least the read lock.
+func (ds *DataStore) zsetOperation(op model.ZSetOp, keys []string, options model.ZSetOpOptions) (*zset, error) {
+	sets := make([]*zset, len(keys))
+	weights := make([]float64, len(keys))
+	for i, key := range keys {
+		zs, err := ds.lookupZset(key)
+		if err != nil {
+			return nil, err
+		}
+		sets[i], weights[i] = zs, 1
+		if options.Weights != nil {
+			weights[i] = options.Weights[i]
+		}
+	}
+	result := newZset()
+	switch op {
+	case model.ZSetUnion:
+		for i, zs := range sets {
+			if zs == nil {
+				continue
+			}
+			for x := zs.ordered.header.next(); x != nil; x = x.next() {
+				score := weightedScore(x.score, weights[i])
+				if current, ok := result.scores.get(x.member); ok {
+					score = aggregateScores(current, score, options.Aggregate)
+				}
+				result.add(x.member, score)
+			}
+		}
+	case model.ZSetInter:
+		for _, zs := range sets {
+			if zs == nil {
+				return result, nil
+			}
+		}
+		// walking the smallest set checks the fewest members
+		order := make([]int, len(sets))
+		for i := range order {
+			order[i] = i
+		}
+		sort.SliceStable(order, func(a, b int) bool { return sets[order[a]].len() < sets[order[b]].len() })
+		smallest := sets[order[0]]
+		for x := smallest.ordered.header.next(); x != nil; x = x.next() {
+			score := weightedScore(x.score, weights[order[0]])
+			found := true
+			for _, i := range order[1:] {
+				other, ok := sets[i].scores.get(x.member)
+				if !ok {
+					found = false
+					break
+				}
+				score = aggregateScores(score, weightedScore(other, weights[i]), options.Aggregate)
+			}
+			if found {
+				result.add(x.member, score)
+			}
+		}
+	case model.ZSetDiff:
+		if sets[0] == nil {
+			return result, nil
+		}
+		for x := sets[0].ordered.header.next(); x != nil; x = x.next() {
+			found := false
+			for _, other := range sets[1:] {
+				if other == nil {
+					continue
+				}
+				if _, found = other.scores.get(x.member); found {
+					break
+				}
+			}
+			if !found {
+				result.add(x.member, x.score)
+			}
+		}
+	}
+	return result, nil
+}
+
+// weightedScore multiplies score by weight, 0 times infinity gives 0 like in
+// redis.
+func weightedScore(score float64, weight float64) float64 {
+	weighted := score * weight
+	if math.IsNaN(weighted) {
+		return 0
+	}
+	return weighted
+}
+
+func aggregateScores(a float64, b float64, aggregate model.Aggregate) float64 {
+	switch aggregate {
+	case model.AggregateMin:
+		return math.Min(a, b)
+	case model.AggregateMax:
+		return math.Max(a, b)
+	default:
+		// +inf plus -inf
+		if sum := a + b; !math.IsNaN(sum) {
+			return sum
+		}
+		return 0
+	}
+}
+
+// ZSetOperation returns the union, intersection or difference of the sorted
+// sets at keys, ordered like ZRANGE.
+func (ds *DataStore) ZSetOperation(op model.ZSetOp, keys []string, options model.ZSetOpOptions) ([]model.SortedSet, error) {
+	log.Printf("Combining the sorted sets %v with operation %d and options %+v\n", keys, op, options)
+	ds.lock.RLock()
+	defer ds.lock.RUnlock()
+	result, err := ds.zsetOperation(op, keys, options)
+	if err != nil {
+		return nil, err
+	}
+	return result.rangeBySpec(model.ZRangeSpec{By: model.ZRangeByRank, Start: 0, Stop: -1}), nil
+}
+
+// ZSetOperationStore stores the union, intersection or difference of the
+// sorted sets at keys in dst, replacing any value there, and returns its
+// number of members. An empty result deletes dst.
+func (ds *DataStore) ZSetOperationStore(dst string, op model.ZSetOp, keys []string, options model.ZSetOpOptions) (int, error) {
+	log.Printf("Storing the sorted sets %v combined with operation %d and options %+v in %s\n", keys, op, options, dst)
+	ds.lock.Lock()
+	defer ds.lock.Unlock()
+	result, err := ds.zsetOperation(op, keys, options)
+	if err != nil {
+		return 0, err
+	}
+	ds.deleteKey(dst)
+	if result.len() > 0 {
+		ds.data.set(dst, result)
+		ds.signalKeyReady(dst)
+	}
+	return result.len(), nil
+}
+
+// ZInterCard returns the number of members in the intersection of the sorted
+// sets at keys, counting stops at limit unless it is 0. Members are only
+// counted, the intersection itself isn't built.
+func (ds *DataStore) ZInterCard(keys []string, limit int) (int, error) {
+	log.Printf("Counting the intersection of the sorted sets %v\n", keys)
+	ds.lock.RLock()
+	defer ds.lock.RUnlock()
+	sets := make([]*zset, len(keys))
+	for i, key := range keys {
+		zs, err := ds.lookupZset(key)
+		if err != nil {
+			return 0, err
+		}
+		sets[i] = zs
+	}
+	if slices.Contains(sets, nil) {
+		return 0, nil
+	}
+	// walking the smallest set checks the fewest members
+	sort.SliceStable(sets, func(a, b int) bool { return sets[a].len() < sets[b].len() })
+	count := 0
+	for x := sets[0].ordered.header.next(); x != nil; x = x.next() {
+		found := true
+		for _, other := range sets[1:] {
+			if _, found = other.scores.get(x.member); !found {
+				break
+			}
+		}
+		if !found {
+			continue
+		}
+		count++
+		if count == limit {
+			break
+		}
+	}
+	return count, nil
+}
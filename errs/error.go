@@ -11,4 +11,41 @@ var (
 	InvalidFloatValue = errors.New("value is not a valid float")
 	InvalidIntValue   = errors.New("value is not a valid int")
 	SyntaxError       = errors.New("syntax error")
+	UnknownSubcommand = errors.New("unknown subcommand")
+	InvalidCursor     = errors.New("invalid cursor")
+	NoSuchKey         = errors.New("no such key")
+	IndexOutOfRange   = errors.New("index out of range")
+	HashValueNotInt   = errors.New("hash value is not an integer")
+	HashValueNotFloat = errors.New("hash value is not a float")
+	IncrOverflow      = errors.New("increment or decrement would overflow")
+	IncrNaNOrInfinity = errors.New("increment would produce NaN or Infinity")
+
+	ZAddNXXXIncompatible   = errors.New("XX and NX options at the same time are not compatible")
+	ZAddGTLTNXIncompatible = errors.New("GT, LT, and/or NX options at the same time are not compatible")
+	ZAddIncrSinglePair     = errors.New("INCR option supports a single increment-element pair")
+	NoInputKeys            = errors.New("at least 1 input key is needed")
+	InvalidWeight          = errors.New("weight value is not a float")
+	NegativeLimit          = errors.New("LIMIT can't be negative")
+	NegativeCount          = errors.New("value is out of range, must be positive")
+	CountNotPositive       = errors.New("count should be greater than 0")
+	InvalidTimeout         = errors.New("timeout is not a float or out of range")
+	NegativeTimeout        = errors.New("timeout is negative")
+	ScoreNaN               = errors.New("resulting score is not a number (NaN)")
+	InvalidScoreRange      = errors.New("min or max is not a float")
+	InvalidLexRange        = errors.New("min or max not valid string range item")
+	LimitWithoutBy         = errors.New("syntax error, LIMIT is only supported in combination with either BYSCORE or BYLEX")
+	WithScoresByLex        = errors.New("syntax error, WITHSCORES not supported in combination with BYLEX")
+
+	InvalidExpireTime      = errors.New("invalid expire time")
+	ExpireNXIncompatible   = errors.New("NX and XX, GT or LT options at the same time are not compatible")
+	ExpireGTLTIncompatible = errors.New("GT and LT options at the same time are not compatible")
+
+	InvalidMultiBulkLength = errors.New("Protocol error: invalid multibulk length")
+	InvalidBulkLength      = errors.New("Protocol error: invalid bulk length")
+	ExpectedBulkString     = errors.New("Protocol error: expected '$'")
+	TooBigInlineRequest    = errors.New("Protocol error: too big inline request")
+	UnbalancedQuotes       = errors.New("Protocol error: unbalanced quotes in request")
+
+	NoProto             = errors.New("NOPROTO unsupported protocol version")
+	InvalidProtoVersion = errors.New("Protocol version is not an integer or out of range")
 )
@@ -1,5 +1,3 @@
 module github.com/saurabhy27/redis-database
 
 go 1.21.3
-
-require github.com/huandu/skiplist v1.2.0
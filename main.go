@@ -1,9 +1,15 @@
 package main
 
 import (
+	"context"
 	"log"
+	"os"
+	"os/signal"
 	"strconv"
+	"syscall"
+	"time"
 
+	"github.com/saurabhy27/redis-database/constants"
 	"github.com/saurabhy27/redis-database/datastore"
 	"github.com/saurabhy27/redis-database/processor"
 	"github.com/saurabhy27/redis-database/server"
@@ -19,6 +25,23 @@ func main() {
 	if err != nil {
 		panic(err)
 	}
-	args := server.ServerArgs{Port: port}
-	server.New(args, commandProcessor).Start()
+	maxBulkLen, err := strconv.Atoi(utils.GetEnv("PROTO_MAX_BULK_LEN", strconv.Itoa(constants.ProtoMaxBulkLen)))
+	if err != nil {
+		panic(err)
+	}
+	shutdownTimeout, err := strconv.Atoi(utils.GetEnv("SHUTDOWN_TIMEOUT", strconv.Itoa(constants.ShutdownTimeout)))
+	if err != nil {
+		panic(err)
+	}
+	args := server.ServerArgs{
+		Port:            port,
+		MaxBulkLen:      maxBulkLen,
+		ShutdownTimeout: time.Duration(shutdownTimeout) * time.Second,
+	}
+	// SIGINT and SIGTERM stop the server gracefully
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	go datastore.RunActiveExpire(ctx)
+	server.New(args, commandProcessor).Start(ctx)
+	log.Println("Project Execution Finished")
 }
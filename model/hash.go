@@ -0,0 +1,7 @@
+package model
+
+// FieldValue is a field of a hash and its value.
+type FieldValue struct {
+	Field string
+	Value string
+}
@@ -1,11 +1,43 @@
 package model
 
+// Command describes an entry of the command table.
 type Command struct {
-	Cmd          string
-	MinReqParams int
+	Cmd      string   // upper case command name
+	Arity    int      // arguments including the name, negative means at least -Arity
+	Flags    []string // like write, readonly or movablekeys
+	Category string   // acl category of the data type, like @sortedset
+	FirstKey int      // position of the first key argument, 0 when there are no keys
+	LastKey  int      // position of the last key, negative counts from the end
+	KeyStep  int      // distance between two key arguments
+	Summary  string   // one line description shown by COMMAND DOCS
 }
 
 type Request struct {
 	Command Command
 	Params  []string
+	// closed when a blocking command has to stop waiting because the client
+	// went away or the server shuts down, nil waits for the timeout
+	Done <-chan struct{}
+}
+
+// ExpireCondition restricts when EXPIRE and its variants replace a TTL, the
+// flags can be combined.
+type ExpireCondition int
+
+const ExpireAlways ExpireCondition = 0
+
+const (
+	ExpireNX ExpireCondition = 1 << iota // only when the key has no TTL
+	ExpireXX                             // only when the key has a TTL
+	ExpireGT                             // only when the new TTL is greater
+	ExpireLT                             // only when the new TTL is lower
+)
+
+// SetOptions holds the options of SET and its variants.
+type SetOptions struct {
+	NX       bool  // only set a key that doesn't exist
+	XX       bool  // only set a key that already exists
+	Get      bool  // return the old value
+	KeepTTL  bool  // keep the TTL of the old value
+	Deadline int64 // TTL as a unix time in milliseconds, 0 for none
 }
@@ -4,3 +4,21 @@ type Responce struct {
 	Success bool
 	Value   any
 }
+
+// Map is an ordered list of key value pairs. It is sent as a native map to
+// RESP3 clients and flattened into an array for everyone else.
+type Map []KeyValue
+
+type KeyValue struct {
+	Key   string
+	Value any
+}
+
+// NullArray is the reply of the commands answering with an array when there
+// is nothing to return, like a blocking pop that timed out. It is a null
+// array for RESP2 clients instead of the null bulk string of a nil value.
+type NullArray struct{}
+
+// Set holds status replies, like command flags. It is sent as a native set
+// to RESP3 clients and as an array otherwise.
+type Set []string
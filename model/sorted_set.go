@@ -9,3 +9,77 @@ type SortedSetByte struct {
 	Score  float64
 	Member []byte
 }
+
+// ZAddOptions holds the flags of ZADD.
+type ZAddOptions struct {
+	NX   bool // only add new members
+	XX   bool // only update members that already exist
+	GT   bool // only update a score to a greater one
+	LT   bool // only update a score to a lower one
+	CH   bool // count the members whose score changed too
+	Incr bool // increment the score of the member like ZINCRBY
+}
+
+// ZRangeBy tells how the bounds of a sorted set range are read.
+type ZRangeBy int
+
+const (
+	ZRangeByRank ZRangeBy = iota
+	ZRangeByScore
+	ZRangeByLex
+)
+
+// ScoreRange holds the scores from Min to Max, a bound is left out when it
+// is exclusive.
+type ScoreRange struct {
+	Min, Max                   float64
+	MinExclusive, MaxExclusive bool
+}
+
+// LexRange holds the members from Min to Max, a bound is left out when it is
+// exclusive. An infinite bound stands for - as Min and + as Max.
+type LexRange struct {
+	Min, Max                   string
+	MinExclusive, MaxExclusive bool
+	MinInfinite, MaxInfinite   bool
+}
+
+// ZRangeSpec describes the members a ZRANGE returns, Start and Stop for a
+// range by rank, Score or Lex for the others. Rev walks the set from the
+// highest score, ranks then count from the end. Offset and Count are the
+// LIMIT of score and lex ranges, a negative Count returns every member.
+type ZRangeSpec struct {
+	By          ZRangeBy
+	Start, Stop int
+	Score       ScoreRange
+	Lex         LexRange
+	Rev         bool
+	Offset      int
+	Count       int
+}
+
+// ZSetOp is the operation ZUNION, ZINTER and ZDIFF run over sorted sets.
+type ZSetOp int
+
+const (
+	ZSetUnion ZSetOp = iota
+	ZSetInter
+	ZSetDiff
+)
+
+// Aggregate tells how the scores of a member found in several sorted sets
+// are combined.
+type Aggregate int
+
+const (
+	AggregateSum Aggregate = iota
+	AggregateMin
+	AggregateMax
+)
+
+// ZSetOpOptions holds the WEIGHTS and AGGREGATE options of ZUNION and ZINTER.
+// No weights count as a weight of 1 for every set.
+type ZSetOpOptions struct {
+	Weights   []float64
+	Aggregate Aggregate
+}
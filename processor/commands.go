@@ -0,0 +1,631 @@
+package processor
+
+import (
+	"github.com/saurabhy27/redis-database/constants"
+	"github.com/saurabhy27/redis-database/model"
+	req "github.com/saurabhy27/redis-database/request"
+)
+
+type handler func(rp *RequestProcessor, request model.Request) (model.Responce, error)
+
+type commandEntry struct {
+	command model.Command
+	handler handler
+}
+
+// commandTable holds every command run by the request processor. Adding a
+// command only needs an entry here, the request parser validates its arity
+// and Process dispatches to its handler.
+var commandTable = []commandEntry{
+	{
+		command: model.Command{Cmd: constants.GET, Arity: 2, FirstKey: 1, LastKey: 1, KeyStep: 1,
+			Flags:    []string{constants.FlagReadonly, constants.FlagFast},
+			Category: constants.CategoryString,
+			Summary:  "Returns the string value of a key."},
+		handler: (*RequestProcessor).processGet,
+	},
+	{
+		command: model.Command{Cmd: constants.SET, Arity: -3, FirstKey: 1, LastKey: 1, KeyStep: 1,
+			Flags:    []string{constants.FlagWrite},
+			Category: constants.CategoryString,
+			Summary:  "Sets the string value of a key."},
+		handler: (*RequestProcessor).processSet,
+	},
+	{
+		command: model.Command{Cmd: constants.SETEX, Arity: 4, FirstKey: 1, LastKey: 1, KeyStep: 1,
+			Flags:    []string{constants.FlagWrite},
+			Category: constants.CategoryString,
+			Summary:  "Sets the string value and expiration time in seconds of a key."},
+		handler: (*RequestProcessor).processSetEx,
+	},
+	{
+		command: model.Command{Cmd: constants.PSETEX, Arity: 4, FirstKey: 1, LastKey: 1, KeyStep: 1,
+			Flags:    []string{constants.FlagWrite},
+			Category: constants.CategoryString,
+			Summary:  "Sets the string value and expiration time in milliseconds of a key."},
+		handler: (*RequestProcessor).processPSetEx,
+	},
+	{
+		command: model.Command{Cmd: constants.SETNX, Arity: 3, FirstKey: 1, LastKey: 1, KeyStep: 1,
+			Flags:    []string{constants.FlagWrite, constants.FlagFast},
+			Category: constants.CategoryString,
+			Summary:  "Sets the string value of a key only when the key doesn't exist."},
+		handler: (*RequestProcessor).processSetNx,
+	},
+	{
+		command: model.Command{Cmd: constants.GETSET, Arity: 3, FirstKey: 1, LastKey: 1, KeyStep: 1,
+			Flags:    []string{constants.FlagWrite, constants.FlagFast},
+			Category: constants.CategoryString,
+			Summary:  "Returns the previous string value of a key after setting it to a new value."},
+		handler: (*RequestProcessor).processGetSet,
+	},
+	{
+		command: model.Command{Cmd: constants.GETDEL, Arity: 2, FirstKey: 1, LastKey: 1, KeyStep: 1,
+			Flags:    []string{constants.FlagWrite, constants.FlagFast},
+			Category: constants.CategoryString,
+			Summary:  "Returns the string value of a key after deleting the key."},
+		handler: (*RequestProcessor).processGetDel,
+	},
+	{
+		command: model.Command{Cmd: constants.GETEX, Arity: -2, FirstKey: 1, LastKey: 1, KeyStep: 1,
+			Flags:    []string{constants.FlagWrite, constants.FlagFast},
+			Category: constants.CategoryString,
+			Summary:  "Returns the string value of a key after setting its expiration time."},
+		handler: (*RequestProcessor).processGetEx,
+	},
+	{
+		command: model.Command{Cmd: constants.DEL, Arity: -2, FirstKey: 1, LastKey: -1, KeyStep: 1,
+			Flags:    []string{constants.FlagWrite},
+			Category: constants.CategoryKeyspace,
+			Summary:  "Deletes one or more keys."},
+		handler: (*RequestProcessor).processDel,
+	},
+	{
+		command: model.Command{Cmd: constants.KEYS, Arity: 2,
+			Flags:    []string{constants.FlagReadonly},
+			Category: constants.CategoryKeyspace,
+			Summary:  "Returns all key names that match a pattern."},
+		handler: (*RequestProcessor).processKeys,
+	},
+	{
+		command: model.Command{Cmd: constants.SCAN, Arity: -2,
+			Flags:    []string{constants.FlagReadonly},
+			Category: constants.CategoryKeyspace,
+			Summary:  "Iterates over the key names in the database."},
+		handler: (*RequestProcessor).processScan,
+	},
+	{
+		command: model.Command{Cmd: constants.EXPIRE, Arity: -3, FirstKey: 1, LastKey: 1, KeyStep: 1,
+			Flags:    []string{constants.FlagWrite, constants.FlagFast},
+			Category: constants.CategoryKeyspace,
+			Summary:  "Sets the expiration time of a key in seconds."},
+		handler: (*RequestProcessor).processExpire,
+	},
+	{
+		command: model.Command{Cmd: constants.PEXPIRE, Arity: -3, FirstKey: 1, LastKey: 1, KeyStep: 1,
+			Flags:    []string{constants.FlagWrite, constants.FlagFast},
+			Category: constants.CategoryKeyspace,
+			Summary:  "Sets the expiration time of a key in milliseconds."},
+		handler: (*RequestProcessor).processPExpire,
+	},
+	{
+		command: model.Command{Cmd: constants.EXPIREAT, Arity: -3, FirstKey: 1, LastKey: 1, KeyStep: 1,
+			Flags:    []string{constants.FlagWrite, constants.FlagFast},
+			Category: constants.CategoryKeyspace,
+			Summary:  "Sets the expiration time of a key to a Unix timestamp."},
+		handler: (*RequestProcessor).processExpireAt,
+	},
+	{
+		command: model.Command{Cmd: constants.PEXPIREAT, Arity: -3, FirstKey: 1, LastKey: 1, KeyStep: 1,
+			Flags:    []string{constants.FlagWrite, constants.FlagFast},
+			Category: constants.CategoryKeyspace,
+			Summary:  "Sets the expiration time of a key to a Unix milliseconds timestamp."},
+		handler: (*RequestProcessor).processPExpireAt,
+	},
+	{
+		command: model.Command{Cmd: constants.PERSIST, Arity: 2, FirstKey: 1, LastKey: 1, KeyStep: 1,
+			Flags:    []string{constants.FlagWrite, constants.FlagFast},
+			Category: constants.CategoryKeyspace,
+			Summary:  "Removes the expiration time of a key."},
+		handler: (*RequestProcessor).processPersist,
+	},
+	{
+		command: model.Command{Cmd: constants.TTL, Arity: 2, FirstKey: 1, LastKey: 1, KeyStep: 1,
+			Flags:    []string{constants.FlagReadonly, constants.FlagFast},
+			Category: constants.CategoryKeyspace,
+			Summary:  "Returns the expiration time in seconds of a key."},
+		handler: (*RequestProcessor).processTtl,
+	},
+	{
+		command: model.Command{Cmd: constants.PTTL, Arity: 2, FirstKey: 1, LastKey: 1, KeyStep: 1,
+			Flags:    []string{constants.FlagReadonly, constants.FlagFast},
+			Category: constants.CategoryKeyspace,
+			Summary:  "Returns the expiration time in milliseconds of a key."},
+		handler: (*RequestProcessor).processPTtl,
+	},
+	{
+		command: model.Command{Cmd: constants.EXPIRETIME, Arity: 2, FirstKey: 1, LastKey: 1, KeyStep: 1,
+			Flags:    []string{constants.FlagReadonly, constants.FlagFast},
+			Category: constants.CategoryKeyspace,
+			Summary:  "Returns the expiration time of a key as a Unix timestamp."},
+		handler: (*RequestProcessor).processExpireTime,
+	},
+	{
+		command: model.Command{Cmd: constants.PEXPIRETIME, Arity: 2, FirstKey: 1, LastKey: 1, KeyStep: 1,
+			Flags:    []string{constants.FlagReadonly, constants.FlagFast},
+			Category: constants.CategoryKeyspace,
+			Summary:  "Returns the expiration time of a key as a Unix milliseconds timestamp."},
+		handler: (*RequestProcessor).processPExpireTime,
+	},
+	{
+		command: model.Command{Cmd: constants.ZADD, Arity: -4, FirstKey: 1, LastKey: 1, KeyStep: 1,
+			Flags:    []string{constants.FlagWrite, constants.FlagFast},
+			Category: constants.CategorySortedSet,
+			Summary:  "Adds one or more members to a sorted set."},
+		handler: (*RequestProcessor).processZAdd,
+	},
+	{
+		command: model.Command{Cmd: constants.ZRANGE, Arity: -4, FirstKey: 1, LastKey: 1, KeyStep: 1,
+			Flags:    []string{constants.FlagReadonly},
+			Category: constants.CategorySortedSet,
+			Summary:  "Returns members in a sorted set within a range of indexes, scores or members."},
+		handler: (*RequestProcessor).processZRange,
+	},
+	{
+		command: model.Command{Cmd: constants.ZREVRANGE, Arity: -4, FirstKey: 1, LastKey: 1, KeyStep: 1,
+			Flags:    []string{constants.FlagReadonly},
+			Category: constants.CategorySortedSet,
+			Summary:  "Returns members in a sorted set within a range of indexes in reverse order."},
+		handler: (*RequestProcessor).processZRevRange,
+	},
+	{
+		command: model.Command{Cmd: constants.ZRANGEBYSCORE, Arity: -4, FirstKey: 1, LastKey: 1, KeyStep: 1,
+			Flags:    []string{constants.FlagReadonly},
+			Category: constants.CategorySortedSet,
+			Summary:  "Returns members in a sorted set within a range of scores."},
+		handler: (*RequestProcessor).processZRangeByScore,
+	},
+	{
+		command: model.Command{Cmd: constants.ZREVRANGEBYSCORE, Arity: -4, FirstKey: 1, LastKey: 1, KeyStep: 1,
+			Flags:    []string{constants.FlagReadonly},
+			Category: constants.CategorySortedSet,
+			Summary:  "Returns members in a sorted set within a range of scores in reverse order."},
+		handler: (*RequestProcessor).processZRevRangeByScore,
+	},
+	{
+		command: model.Command{Cmd: constants.ZRANGEBYLEX, Arity: -4, FirstKey: 1, LastKey: 1, KeyStep: 1,
+			Flags:    []string{constants.FlagReadonly},
+			Category: constants.CategorySortedSet,
+			Summary:  "Returns members in a sorted set within a lexicographical range."},
+		handler: (*RequestProcessor).processZRangeByLex,
+	},
+	{
+		command: model.Command{Cmd: constants.ZREVRANGEBYLEX, Arity: -4, FirstKey: 1, LastKey: 1, KeyStep: 1,
+			Flags:    []string{constants.FlagReadonly},
+			Category: constants.CategorySortedSet,
+			Summary:  "Returns members in a sorted set within a lexicographical range in reverse order."},
+		handler: (*RequestProcessor).processZRevRangeByLex,
+	},
+	{
+		command: model.Command{Cmd: constants.ZRANGESTORE, Arity: -5, FirstKey: 1, LastKey: 2, KeyStep: 1,
+			Flags:    []string{constants.FlagWrite},
+			Category: constants.CategorySortedSet,
+			Summary:  "Stores a range of members from a sorted set in a key."},
+		handler: (*RequestProcessor).processZRangeStore,
+	},
+	{
+		command: model.Command{Cmd: constants.ZSCAN, Arity: -3, FirstKey: 1, LastKey: 1, KeyStep: 1,
+			Flags:    []string{constants.FlagReadonly},
+			Category: constants.CategorySortedSet,
+			Summary:  "Iterates over members and scores of a sorted set."},
+		handler: (*RequestProcessor).processZScan,
+	},
+	{
+		command: model.Command{Cmd: constants.ZRANK, Arity: -3, FirstKey: 1, LastKey: 1, KeyStep: 1,
+			Flags:    []string{constants.FlagReadonly, constants.FlagFast},
+			Category: constants.CategorySortedSet,
+			Summary:  "Returns the index of a member in a sorted set ordered by ascending scores."},
+		handler: (*RequestProcessor).processZRank,
+	},
+	{
+		command: model.Command{Cmd: constants.ZREVRANK, Arity: -3, FirstKey: 1, LastKey: 1, KeyStep: 1,
+			Flags:    []string{constants.FlagReadonly, constants.FlagFast},
+			Category: constants.CategorySortedSet,
+			Summary:  "Returns the index of a member in a sorted set ordered by descending scores."},
+		handler: (*RequestProcessor).processZRevRank,
+	},
+	{
+		command: model.Command{Cmd: constants.ZSCORE, Arity: 3, FirstKey: 1, LastKey: 1, KeyStep: 1,
+			Flags:    []string{constants.FlagReadonly, constants.FlagFast},
+			Category: constants.CategorySortedSet,
+			Summary:  "Returns the score of a member in a sorted set."},
+		handler: (*RequestProcessor).processZScore,
+	},
+	{
+		command: model.Command{Cmd: constants.ZMSCORE, Arity: -3, FirstKey: 1, LastKey: 1, KeyStep: 1,
+			Flags:    []string{constants.FlagReadonly, constants.FlagFast},
+			Category: constants.CategorySortedSet,
+			Summary:  "Returns the score of one or more members in a sorted set."},
+		handler: (*RequestProcessor).processZMScore,
+	},
+	{
+		command: model.Command{Cmd: constants.ZCARD, Arity: 2, FirstKey: 1, LastKey: 1, KeyStep: 1,
+			Flags:    []string{constants.FlagReadonly, constants.FlagFast},
+			Category: constants.CategorySortedSet,
+			Summary:  "Returns the number of members in a sorted set."},
+		handler: (*RequestProcessor).processZCard,
+	},
+	{
+		command: model.Command{Cmd: constants.ZCOUNT, Arity: 4, FirstKey: 1, LastKey: 1, KeyStep: 1,
+			Flags:    []string{constants.FlagReadonly, constants.FlagFast},
+			Category: constants.CategorySortedSet,
+			Summary:  "Returns the count of members in a sorted set that have scores within a range."},
+		handler: (*RequestProcessor).processZCount,
+	},
+	{
+		command: model.Command{Cmd: constants.ZLEXCOUNT, Arity: 4, FirstKey: 1, LastKey: 1, KeyStep: 1,
+			Flags:    []string{constants.FlagReadonly, constants.FlagFast},
+			Category: constants.CategorySortedSet,
+			Summary:  "Returns the number of members in a sorted set within a lexicographical range."},
+		handler: (*RequestProcessor).processZLexCount,
+	},
+	{
+		command: model.Command{Cmd: constants.ZINCRBY, Arity: 4, FirstKey: 1, LastKey: 1, KeyStep: 1,
+			Flags:    []string{constants.FlagWrite, constants.FlagFast},
+			Category: constants.CategorySortedSet,
+			Summary:  "Increments the score of a member in a sorted set."},
+		handler: (*RequestProcessor).processZIncrBy,
+	},
+	{
+		command: model.Command{Cmd: constants.ZREM, Arity: -3, FirstKey: 1, LastKey: 1, KeyStep: 1,
+			Flags:    []string{constants.FlagWrite, constants.FlagFast},
+			Category: constants.CategorySortedSet,
+			Summary:  "Removes one or more members from a sorted set."},
+		handler: (*RequestProcessor).processZRem,
+	},
+	{
+		command: model.Command{Cmd: constants.ZREMRANGEBYRANK, Arity: 4, FirstKey: 1, LastKey: 1, KeyStep: 1,
+			Flags:    []string{constants.FlagWrite},
+			Category: constants.CategorySortedSet,
+			Summary:  "Removes members in a sorted set within a range of indexes."},
+		handler: (*RequestProcessor).processZRemRangeByRank,
+	},
+	{
+		command: model.Command{Cmd: constants.ZREMRANGEBYSCORE, Arity: 4, FirstKey: 1, LastKey: 1, KeyStep: 1,
+			Flags:    []string{constants.FlagWrite},
+			Category: constants.CategorySortedSet,
+			Summary:  "Removes members in a sorted set within a range of scores."},
+		handler: (*RequestProcessor).processZRemRangeByScore,
+	},
+	{
+		command: model.Command{Cmd: constants.ZREMRANGEBYLEX, Arity: 4, FirstKey: 1, LastKey: 1, KeyStep: 1,
+			Flags:    []string{constants.FlagWrite},
+			Category: constants.CategorySortedSet,
+			Summary:  "Removes members in a sorted set within a lexicographical range."},
+		handler: (*RequestProcessor).processZRemRangeByLex,
+	},
+	{
+		command: model.Command{Cmd: constants.ZUNION, Arity: -3,
+			Flags:    []string{constants.FlagReadonly, constants.FlagMovableKeys},
+			Category: constants.CategorySortedSet,
+			Summary:  "Returns the union of multiple sorted sets."},
+		handler: (*RequestProcessor).processZUnion,
+	},
+	{
+		command: model.Command{Cmd: constants.ZINTER, Arity: -3,
+			Flags:    []string{constants.FlagReadonly, constants.FlagMovableKeys},
+			Category: constants.CategorySortedSet,
+			Summary:  "Returns the intersect of multiple sorted sets."},
+		handler: (*RequestProcessor).processZInter,
+	},
+	{
+		command: model.Command{Cmd: constants.ZDIFF, Arity: -3,
+			Flags:    []string{constants.FlagReadonly, constants.FlagMovableKeys},
+			Category: constants.CategorySortedSet,
+			Summary:  "Returns the difference between multiple sorted sets."},
+		handler: (*RequestProcessor).processZDiff,
+	},
+	{
+		command: model.Command{Cmd: constants.ZUNIONSTORE, Arity: -4, FirstKey: 1, LastKey: 1, KeyStep: 1,
+			Flags:    []string{constants.FlagWrite, constants.FlagMovableKeys},
+			Category: constants.CategorySortedSet,
+			Summary:  "Stores the union of multiple sorted sets in a key."},
+		handler: (*RequestProcessor).processZUnionStore,
+	},
+	{
+		command: model.Command{Cmd: constants.ZINTERSTORE, Arity: -4, FirstKey: 1, LastKey: 1, KeyStep: 1,
+			Flags:    []string{constants.FlagWrite, constants.FlagMovableKeys},
+			Category: constants.CategorySortedSet,
+			Summary:  "Stores the intersect of multiple sorted sets in a key."},
+		handler: (*RequestProcessor).processZInterStore,
+	},
+	{
+		command: model.Command{Cmd: constants.ZDIFFSTORE, Arity: -4, FirstKey: 1, LastKey: 1, KeyStep: 1,
+			Flags:    []string{constants.FlagWrite, constants.FlagMovableKeys},
+			Category: constants.CategorySortedSet,
+			Summary:  "Stores the difference of multiple sorted sets in a key."},
+		handler: (*RequestProcessor).processZDiffStore,
+	},
+	{
+		command: model.Command{Cmd: constants.ZINTERCARD, Arity: -3,
+			Flags:    []string{constants.FlagReadonly, constants.FlagMovableKeys},
+			Category: constants.CategorySortedSet,
+			Summary:  "Returns the number of members of the intersect of multiple sorted sets."},
+		handler: (*RequestProcessor).processZInterCard,
+	},
+	{
+		command: model.Command{Cmd: constants.ZPOPMIN, Arity: -2, FirstKey: 1, LastKey: 1, KeyStep: 1,
+			Flags:    []string{constants.FlagWrite, constants.FlagFast},
+			Category: constants.CategorySortedSet,
+			Summary:  "Returns the lowest-scoring members from a sorted set after removing them."},
+		handler: (*RequestProcessor).processZPopMin,
+	},
+	{
+		command: model.Command{Cmd: constants.ZPOPMAX, Arity: -2, FirstKey: 1, LastKey: 1, KeyStep: 1,
+			Flags:    []string{constants.FlagWrite, constants.FlagFast},
+			Category: constants.CategorySortedSet,
+			Summary:  "Returns the highest-scoring members from a sorted set after removing them."},
+		handler: (*RequestProcessor).processZPopMax,
+	},
+	{
+		command: model.Command{Cmd: constants.BZPOPMIN, Arity: -3, FirstKey: 1, LastKey: -2, KeyStep: 1,
+			Flags:    []string{constants.FlagWrite, constants.FlagFast, constants.FlagBlocking},
+			Category: constants.CategorySortedSet,
+			Summary:  "Removes and returns the member with the lowest score from one or more sorted sets. Blocks until a member is available otherwise."},
+		handler: (*RequestProcessor).processBZPopMin,
+	},
+	{
+		command: model.Command{Cmd: constants.BZPOPMAX, Arity: -3, FirstKey: 1, LastKey: -2, KeyStep: 1,
+			Flags:    []string{constants.FlagWrite, constants.FlagFast, constants.FlagBlocking},
+			Category: constants.CategorySortedSet,
+			Summary:  "Removes and returns the member with the highest score from one or more sorted sets. Blocks until a member is available otherwise."},
+		handler: (*RequestProcessor).processBZPopMax,
+	},
+	{
+		command: model.Command{Cmd: constants.ZMPOP, Arity: -4,
+			Flags:    []string{constants.FlagWrite, constants.FlagMovableKeys},
+			Category: constants.CategorySortedSet,
+			Summary:  "Returns the highest- or lowest-scoring members from one or more sorted sets after removing them."},
+		handler: (*RequestProcessor).processZMPop,
+	},
+	{
+		command: model.Command{Cmd: constants.BZMPOP, Arity: -5,
+			Flags:    []string{constants.FlagWrite, constants.FlagBlocking, constants.FlagMovableKeys},
+			Category: constants.CategorySortedSet,
+			Summary:  "Removes and returns a member by score from one or more sorted sets. Blocks until a member is available otherwise."},
+		handler: (*RequestProcessor).processBZMPop,
+	},
+	{
+		command: model.Command{Cmd: constants.ZRANDMEMBER, Arity: -2, FirstKey: 1, LastKey: 1, KeyStep: 1,
+			Flags:    []string{constants.FlagReadonly},
+			Category: constants.CategorySortedSet,
+			Summary:  "Returns one or more random members from a sorted set."},
+		handler: (*RequestProcessor).processZRandMember,
+	},
+	{
+		command: model.Command{Cmd: constants.LPUSH, Arity: -3, FirstKey: 1, LastKey: 1, KeyStep: 1,
+			Flags:    []string{constants.FlagWrite, constants.FlagFast},
+			Category: constants.CategoryList,
+			Summary:  "Prepends one or more elements to a list. Creates the key if it doesn't exist."},
+		handler: (*RequestProcessor).processLPush,
+	},
+	{
+		command: model.Command{Cmd: constants.RPUSH, Arity: -3, FirstKey: 1, LastKey: 1, KeyStep: 1,
+			Flags:    []string{constants.FlagWrite, constants.FlagFast},
+			Category: constants.CategoryList,
+			Summary:  "Appends one or more elements to a list. Creates the key if it doesn't exist."},
+		handler: (*RequestProcessor).processRPush,
+	},
+	{
+		command: model.Command{Cmd: constants.LPOP, Arity: -2, FirstKey: 1, LastKey: 1, KeyStep: 1,
+			Flags:    []string{constants.FlagWrite, constants.FlagFast},
+			Category: constants.CategoryList,
+			Summary:  "Returns the first elements in a list after removing it. Deletes the list if the last element was popped."},
+		handler: (*RequestProcessor).processLPop,
+	},
+	{
+		command: model.Command{Cmd: constants.RPOP, Arity: -2, FirstKey: 1, LastKey: 1, KeyStep: 1,
+			Flags:    []string{constants.FlagWrite, constants.FlagFast},
+			Category: constants.CategoryList,
+			Summary:  "Returns and removes the last elements of a list. Deletes the list if the last element was popped."},
+		handler: (*RequestProcessor).processRPop,
+	},
+	{
+		command: model.Command{Cmd: constants.LLEN, Arity: 2, FirstKey: 1, LastKey: 1, KeyStep: 1,
+			Flags:    []string{constants.FlagReadonly, constants.FlagFast},
+			Category: constants.CategoryList,
+			Summary:  "Returns the length of a list."},
+		handler: (*RequestProcessor).processLLen,
+	},
+	{
+		command: model.Command{Cmd: constants.LRANGE, Arity: 4, FirstKey: 1, LastKey: 1, KeyStep: 1,
+			Flags:    []string{constants.FlagReadonly},
+			Category: constants.CategoryList,
+			Summary:  "Returns a range of elements from a list."},
+		handler: (*RequestProcessor).processLRange,
+	},
+	{
+		command: model.Command{Cmd: constants.LINDEX, Arity: 3, FirstKey: 1, LastKey: 1, KeyStep: 1,
+			Flags:    []string{constants.FlagReadonly},
+			Category: constants.CategoryList,
+			Summary:  "Returns an element from a list by its index."},
+		handler: (*RequestProcessor).processLIndex,
+	},
+	{
+		command: model.Command{Cmd: constants.LSET, Arity: 4, FirstKey: 1, LastKey: 1, KeyStep: 1,
+			Flags:    []string{constants.FlagWrite},
+			Category: constants.CategoryList,
+			Summary:  "Sets the value of an element in a list by its index."},
+		handler: (*RequestProcessor).processLSet,
+	},
+	{
+		command: model.Command{Cmd: constants.LREM, Arity: 4, FirstKey: 1, LastKey: 1, KeyStep: 1,
+			Flags:    []string{constants.FlagWrite},
+			Category: constants.CategoryList,
+			Summary:  "Removes elements from a list. Deletes the list if the last element was removed."},
+		handler: (*RequestProcessor).processLRem,
+	},
+	{
+		command: model.Command{Cmd: constants.LTRIM, Arity: 4, FirstKey: 1, LastKey: 1, KeyStep: 1,
+			Flags:    []string{constants.FlagWrite},
+			Category: constants.CategoryList,
+			Summary:  "Removes elements from both ends of a list. Deletes the list if all elements were trimmed."},
+		handler: (*RequestProcessor).processLTrim,
+	},
+	{
+		command: model.Command{Cmd: constants.LINSERT, Arity: 5, FirstKey: 1, LastKey: 1, KeyStep: 1,
+			Flags:    []string{constants.FlagWrite},
+			Category: constants.CategoryList,
+			Summary:  "Inserts an element before or after another element in a list."},
+		handler: (*RequestProcessor).processLInsert,
+	},
+	{
+		command: model.Command{Cmd: constants.BLPOP, Arity: -3, FirstKey: 1, LastKey: -2, KeyStep: 1,
+			Flags:    []string{constants.FlagWrite, constants.FlagBlocking},
+			Category: constants.CategoryList,
+			Summary:  "Removes and returns the first element in a list. Blocks until an element is available otherwise. Deletes the list if the last element was popped."},
+		handler: (*RequestProcessor).processBLPop,
+	},
+	{
+		command: model.Command{Cmd: constants.BRPOP, Arity: -3, FirstKey: 1, LastKey: -2, KeyStep: 1,
+			Flags:    []string{constants.FlagWrite, constants.FlagBlocking},
+			Category: constants.CategoryList,
+			Summary:  "Removes and returns the last element in a list. Blocks until an element is available otherwise. Deletes the list if the last element was popped."},
+		handler: (*RequestProcessor).processBRPop,
+	},
+	{
+		command: model.Command{Cmd: constants.LMOVE, Arity: 5, FirstKey: 1, LastKey: 2, KeyStep: 1,
+			Flags:    []string{constants.FlagWrite},
+			Category: constants.CategoryList,
+			Summary:  "Returns an element after popping it from one list and pushing it to another. Deletes the list if the last element was moved."},
+		handler: (*RequestProcessor).processLMove,
+	},
+	{
+		command: model.Command{Cmd: constants.BLMOVE, Arity: 6, FirstKey: 1, LastKey: 2, KeyStep: 1,
+			Flags:    []string{constants.FlagWrite, constants.FlagBlocking},
+			Category: constants.CategoryList,
+			Summary:  "Pops an element from a list, pushes it to another list and returns it. Blocks until an element is available otherwise. Deletes the list if the last element was moved."},
+		handler: (*RequestProcessor).processBLMove,
+	},
+	{
+		command: model.Command{Cmd: constants.HSET, Arity: -4, FirstKey: 1, LastKey: 1, KeyStep: 1,
+			Flags:    []string{constants.FlagWrite, constants.FlagFast},
+			Category: constants.CategoryHash,
+			Summary:  "Creates or modifies the value of a field in a hash."},
+		handler: (*RequestProcessor).processHSet,
+	},
+	{
+		command: model.Command{Cmd: constants.HSETNX, Arity: 4, FirstKey: 1, LastKey: 1, KeyStep: 1,
+			Flags:    []string{constants.FlagWrite, constants.FlagFast},
+			Category: constants.CategoryHash,
+			Summary:  "Sets the value of a field in a hash only when the field doesn't exist."},
+		handler: (*RequestProcessor).processHSetNX,
+	},
+	{
+		command: model.Command{Cmd: constants.HGET, Arity: 3, FirstKey: 1, LastKey: 1, KeyStep: 1,
+			Flags:    []string{constants.FlagReadonly, constants.FlagFast},
+			Category: constants.CategoryHash,
+			Summary:  "Returns the value of a field in a hash."},
+		handler: (*RequestProcessor).processHGet,
+	},
+	{
+		command: model.Command{Cmd: constants.HMGET, Arity: -3, FirstKey: 1, LastKey: 1, KeyStep: 1,
+			Flags:    []string{constants.FlagReadonly, constants.FlagFast},
+			Category: constants.CategoryHash,
+			Summary:  "Returns the values of all fields in a hash."},
+		handler: (*RequestProcessor).processHMGet,
+	},
+	{
+		command: model.Command{Cmd: constants.HGETALL, Arity: 2, FirstKey: 1, LastKey: 1, KeyStep: 1,
+			Flags:    []string{constants.FlagReadonly},
+			Category: constants.CategoryHash,
+			Summary:  "Returns all fields and values in a hash."},
+		handler: (*RequestProcessor).processHGetAll,
+	},
+	{
+		command: model.Command{Cmd: constants.HDEL, Arity: -3, FirstKey: 1, LastKey: 1, KeyStep: 1,
+			Flags:    []string{constants.FlagWrite, constants.FlagFast},
+			Category: constants.CategoryHash,
+			Summary:  "Deletes one or more fields and their values from a hash. Deletes the hash if no fields remain."},
+		handler: (*RequestProcessor).processHDel,
+	},
+	{
+		command: model.Command{Cmd: constants.HEXISTS, Arity: 3, FirstKey: 1, LastKey: 1, KeyStep: 1,
+			Flags:    []string{constants.FlagReadonly, constants.FlagFast},
+			Category: constants.CategoryHash,
+			Summary:  "Determines whether a field exists in a hash."},
+		handler: (*RequestProcessor).processHExists,
+	},
+	{
+		command: model.Command{Cmd: constants.HLEN, Arity: 2, FirstKey: 1, LastKey: 1, KeyStep: 1,
+			Flags:    []string{constants.FlagReadonly, constants.FlagFast},
+			Category: constants.CategoryHash,
+			Summary:  "Returns the number of fields in a hash."},
+		handler: (*RequestProcessor).processHLen,
+	},
+	{
+		command: model.Command{Cmd: constants.HSTRLEN, Arity: 3, FirstKey: 1, LastKey: 1, KeyStep: 1,
+			Flags:    []string{constants.FlagReadonly, constants.FlagFast},
+			Category: constants.CategoryHash,
+			Summary:  "Returns the length of the value of a field."},
+		handler: (*RequestProcessor).processHStrLen,
+	},
+	{
+		command: model.Command{Cmd: constants.HKEYS, Arity: 2, FirstKey: 1, LastKey: 1, KeyStep: 1,
+			Flags:    []string{constants.FlagReadonly},
+			Category: constants.CategoryHash,
+			Summary:  "Returns all fields in a hash."},
+		handler: (*RequestProcessor).processHKeys,
+	},
+	{
+		command: model.Command{Cmd: constants.HVALS, Arity: 2, FirstKey: 1, LastKey: 1, KeyStep: 1,
+			Flags:    []string{constants.FlagReadonly},
+			Category: constants.CategoryHash,
+			Summary:  "Returns all values in a hash."},
+		handler: (*RequestProcessor).processHVals,
+	},
+	{
+		command: model.Command{Cmd: constants.HINCRBY, Arity: 4, FirstKey: 1, LastKey: 1, KeyStep: 1,
+			Flags:    []string{constants.FlagWrite, constants.FlagFast},
+			Category: constants.CategoryHash,
+			Summary:  "Increments the integer value of a field in a hash by a number. Uses 0 as initial value if the field doesn't exist."},
+		handler: (*RequestProcessor).processHIncrBy,
+	},
+	{
+		command: model.Command{Cmd: constants.HINCRBYFLOAT, Arity: 4, FirstKey: 1, LastKey: 1, KeyStep: 1,
+			Flags:    []string{constants.FlagWrite, constants.FlagFast},
+			Category: constants.CategoryHash,
+			Summary:  "Increments the floating point value of a field by a number. Uses 0 as initial value if the field doesn't exist."},
+		handler: (*RequestProcessor).processHIncrByFloat,
+	},
+	{
+		command: model.Command{Cmd: constants.HRANDFIELD, Arity: -2, FirstKey: 1, LastKey: 1, KeyStep: 1,
+			Flags:    []string{constants.FlagReadonly},
+			Category: constants.CategoryHash,
+			Summary:  "Returns one or more random fields from a hash."},
+		handler: (*RequestProcessor).processHRandField,
+	},
+	{
+		command: model.Command{Cmd: constants.HSCAN, Arity: -3, FirstKey: 1, LastKey: 1, KeyStep: 1,
+			Flags:    []string{constants.FlagReadonly},
+			Category: constants.CategoryHash,
+			Summary:  "Iterates over fields and values of a hash."},
+		handler: (*RequestProcessor).processHScan,
+	},
+	{
+		command: model.Command{Cmd: constants.COMMAND, Arity: -1,
+			Flags:    []string{constants.FlagLoading, constants.FlagStale},
+			Category: constants.CategoryConnection,
+			Summary:  "Returns detailed information about all commands."},
+		handler: (*RequestProcessor).processCommand,
+	},
+}
+
+var handlers = map[string]handler{}
+
+func init() {
+	for _, entry := range commandTable {
+		req.Register(entry.command)
+		handlers[entry.command.Cmd] = entry.handler
+	}
+}
@@ -0,0 +1,131 @@
+package processor
+
+import (
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/saurabhy27/redis-database/errs"
+	"github.com/saurabhy27/redis-database/model"
+)
+
+// processExpire: EXPIRE key seconds [NX | XX | GT | LT]
+func (rp *RequestProcessor) processExpire(request model.Request) (model.Responce, error) {
+	return rp.expireGeneric(request, 1000, false)
+}
+
+// processPExpire: PEXPIRE key milliseconds [NX | XX | GT | LT]
+func (rp *RequestProcessor) processPExpire(request model.Request) (model.Responce, error) {
+	return rp.expireGeneric(request, 1, false)
+}
+
+// processExpireAt: EXPIREAT key unix-time-seconds [NX | XX | GT | LT]
+func (rp *RequestProcessor) processExpireAt(request model.Request) (model.Responce, error) {
+	return rp.expireGeneric(request, 1000, true)
+}
+
+// processPExpireAt: PEXPIREAT key unix-time-milliseconds [NX | XX | GT | LT]
+func (rp *RequestProcessor) processPExpireAt(request model.Request) (model.Responce, error) {
+	return rp.expireGeneric(request, 1, true)
+}
+
+// expireGeneric turns the time argument, counted in unit milliseconds and
+// relative to now unless absolute, into a deadline in unix milliseconds.
+func (rp *RequestProcessor) expireGeneric(request model.Request, unit int64, absolute bool) (model.Responce, error) {
+	key := request.Params[0]
+	when, err := strconv.ParseInt(request.Params[1], 10, 64)
+	if err != nil {
+		return model.Responce{}, errs.InvalidIntValue
+	}
+	condition, err := parseExpireCondition(request.Params[2:])
+	if err != nil {
+		return model.Responce{}, err
+	}
+	deadline, err := toDeadline(when, unit, absolute)
+	if err != nil {
+		return model.Responce{}, err
+	}
+	expires := rp.DataStore.PExpireAt(key, deadline, condition)
+	return model.Responce{Success: true, Value: expires}, nil
+}
+
+// toDeadline converts a time counted in unit milliseconds, relative to now
+// unless absolute, into a unix time in milliseconds.
+func toDeadline(when int64, unit int64, absolute bool) (int64, error) {
+	if when > math.MaxInt64/unit || when < math.MinInt64/unit {
+		return 0, errs.InvalidExpireTime
+	}
+	when *= unit
+	if absolute {
+		return when, nil
+	}
+	now := time.Now().UnixMilli()
+	if when > math.MaxInt64-now {
+		return 0, errs.InvalidExpireTime
+	}
+	return when + now, nil
+}
+
+var expireConditions = map[string]model.ExpireCondition{
+	"NX": model.ExpireNX,
+	"XX": model.ExpireXX,
+	"GT": model.ExpireGT,
+	"LT": model.ExpireLT,
+}
+
+// parseExpireCondition parses the NX, XX, GT and LT options, rejecting the
+// combinations redis rejects.
+func parseExpireCondition(options []string) (model.ExpireCondition, error) {
+	condition := model.ExpireAlways
+	for _, option := range options {
+		flag, ok := expireConditions[strings.ToUpper(option)]
+		if !ok {
+			return condition, errs.SyntaxError
+		}
+		condition |= flag
+	}
+	if condition&model.ExpireNX != 0 && condition&(model.ExpireXX|model.ExpireGT|model.ExpireLT) != 0 {
+		return condition, errs.ExpireNXIncompatible
+	}
+	if condition&model.ExpireGT != 0 && condition&model.ExpireLT != 0 {
+		return condition, errs.ExpireGTLTIncompatible
+	}
+	return condition, nil
+}
+
+// processTtl: TTL key
+func (rp *RequestProcessor) processTtl(request model.Request) (model.Responce, error) {
+	ttl := rp.DataStore.PTtl(request.Params[0])
+	if ttl >= 0 {
+		ttl = (ttl + 500) / 1000
+	}
+	return model.Responce{Success: true, Value: int(ttl)}, nil
+}
+
+// processPTtl: PTTL key
+func (rp *RequestProcessor) processPTtl(request model.Request) (model.Responce, error) {
+	ttl := rp.DataStore.PTtl(request.Params[0])
+	return model.Responce{Success: true, Value: int(ttl)}, nil
+}
+
+// processExpireTime: EXPIRETIME key
+func (rp *RequestProcessor) processExpireTime(request model.Request) (model.Responce, error) {
+	deadline := rp.DataStore.ExpireTime(request.Params[0])
+	if deadline >= 0 {
+		deadline = (deadline + 500) / 1000
+	}
+	return model.Responce{Success: true, Value: int(deadline)}, nil
+}
+
+// processPExpireTime: PEXPIRETIME key
+func (rp *RequestProcessor) processPExpireTime(request model.Request) (model.Responce, error) {
+	deadline := rp.DataStore.ExpireTime(request.Params[0])
+	return model.Responce{Success: true, Value: int(deadline)}, nil
+}
+
+// processPersist: PERSIST key
+func (rp *RequestProcessor) processPersist(request model.Request) (model.Responce, error) {
+	removed := rp.DataStore.Persist(request.Params[0])
+	return model.Responce{Success: true, Value: removed}, nil
+}
@@ -0,0 +1,198 @@
+package processor
+
+import (
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/saurabhy27/redis-database/errs"
+	"github.com/saurabhy27/redis-database/model"
+	"github.com/saurabhy27/redis-database/utils"
+)
+
+// processHSet: HSET key field value [field value ...]
+func (rp *RequestProcessor) processHSet(request model.Request) (model.Responce, error) {
+	params := request.Params[1:]
+	if len(params)%2 != 0 {
+		return model.Responce{}, errs.MinReqParams
+	}
+	fields := make([]model.FieldValue, 0, len(params)/2)
+	for i := 0; i < len(params); i += 2 {
+		fields = append(fields, model.FieldValue{Field: params[i], Value: params[i+1]})
+	}
+	added, err := rp.DataStore.HSet(request.Params[0], fields)
+	if err != nil {
+		return model.Responce{}, err
+	}
+	return model.Responce{Success: true, Value: added}, nil
+}
+
+// processHSetNX: HSETNX key field value
+func (rp *RequestProcessor) processHSetNX(request model.Request) (model.Responce, error) {
+	set, err := rp.DataStore.HSetNX(request.Params[0], request.Params[1], request.Params[2])
+	if err != nil {
+		return model.Responce{}, err
+	}
+	return model.Responce{Success: true, Value: boolToInt(set)}, nil
+}
+
+// processHGet: HGET key field
+func (rp *RequestProcessor) processHGet(request model.Request) (model.Responce, error) {
+	value, err := rp.DataStore.HGet(request.Params[0], request.Params[1])
+	if err != nil {
+		return model.Responce{}, err
+	}
+	return model.Responce{Success: true, Value: value}, nil
+}
+
+// processHMGet: HMGET key field [field ...]
+func (rp *RequestProcessor) processHMGet(request model.Request) (model.Responce, error) {
+	values, err := rp.DataStore.HMGet(request.Params[0], request.Params[1:])
+	if err != nil {
+		return model.Responce{}, err
+	}
+	// missing fields are null replies
+	reply := make([]any, 0, len(values))
+	for _, value := range values {
+		reply = append(reply, value)
+	}
+	return model.Responce{Success: true, Value: reply}, nil
+}
+
+// processHGetAll: HGETALL key
+func (rp *RequestProcessor) processHGetAll(request model.Request) (model.Responce, error) {
+	fields, err := rp.DataStore.HGetAll(request.Params[0])
+	if err != nil {
+		return model.Responce{}, err
+	}
+	reply := make(model.Map, 0, len(fields))
+	for _, field := range fields {
+		reply = append(reply, model.KeyValue{Key: field.Field, Value: []byte(field.Value)})
+	}
+	return model.Responce{Success: true, Value: reply}, nil
+}
+
+// processHDel: HDEL key field [field ...]
+func (rp *RequestProcessor) processHDel(request model.Request) (model.Responce, error) {
+	removed, err := rp.DataStore.HDel(request.Params[0], request.Params[1:])
+	if err != nil {
+		return model.Responce{}, err
+	}
+	return model.Responce{Success: true, Value: removed}, nil
+}
+
+// processHExists: HEXISTS key field
+func (rp *RequestProcessor) processHExists(request model.Request) (model.Responce, error) {
+	exists, err := rp.DataStore.HExists(request.Params[0], request.Params[1])
+	if err != nil {
+		return model.Responce{}, err
+	}
+	return model.Responce{Success: true, Value: boolToInt(exists)}, nil
+}
+
+// processHLen: HLEN key
+func (rp *RequestProcessor) processHLen(request model.Request) (model.Responce, error) {
+	length, err := rp.DataStore.HLen(request.Params[0])
+	if err != nil {
+		return model.Responce{}, err
+	}
+	return model.Responce{Success: true, Value: length}, nil
+}
+
+// processHStrLen: HSTRLEN key field
+func (rp *RequestProcessor) processHStrLen(request model.Request) (model.Responce, error) {
+	length, err := rp.DataStore.HStrLen(request.Params[0], request.Params[1])
+	if err != nil {
+		return model.Responce{}, err
+	}
+	return model.Responce{Success: true, Value: length}, nil
+}
+
+// processHKeys: HKEYS key
+func (rp *RequestProcessor) processHKeys(request model.Request) (model.Responce, error) {
+	fields, err := rp.DataStore.HKeys(request.Params[0], false)
+	if err != nil {
+		return model.Responce{}, err
+	}
+	return model.Responce{Success: true, Value: fields}, nil
+}
+
+// processHVals: HVALS key
+func (rp *RequestProcessor) processHVals(request model.Request) (model.Responce, error) {
+	values, err := rp.DataStore.HKeys(request.Params[0], true)
+	if err != nil {
+		return model.Responce{}, err
+	}
+	return model.Responce{Success: true, Value: values}, nil
+}
+
+// processHIncrBy: HINCRBY key field increment
+func (rp *RequestProcessor) processHIncrBy(request model.Request) (model.Responce, error) {
+	increment, err := strconv.ParseInt(request.Params[2], 10, 64)
+	if err != nil {
+		return model.Responce{}, errs.InvalidIntValue
+	}
+	value, err := rp.DataStore.HIncrBy(request.Params[0], request.Params[1], increment)
+	if err != nil {
+		return model.Responce{}, err
+	}
+	return model.Responce{Success: true, Value: value}, nil
+}
+
+// processHIncrByFloat: HINCRBYFLOAT key field increment
+func (rp *RequestProcessor) processHIncrByFloat(request model.Request) (model.Responce, error) {
+	increment, err := strconv.ParseFloat(request.Params[2], 64)
+	if err != nil || math.IsNaN(increment) || math.IsInf(increment, 0) {
+		return model.Responce{}, errs.InvalidFloatValue
+	}
+	value, err := rp.DataStore.HIncrByFloat(request.Params[0], request.Params[1], increment)
+	if err != nil {
+		return model.Responce{}, err
+	}
+	// like redis the new value comes back as a bulk string
+	return model.Responce{Success: true, Value: []byte(utils.FormatFloat(value))}, nil
+}
+
+// processHRandField: HRANDFIELD key [count [WITHVALUES]]
+func (rp *RequestProcessor) processHRandField(request model.Request) (model.Responce, error) {
+	if len(request.Params) == 1 {
+		fields, err := rp.DataStore.HRandField(request.Params[0], 1)
+		if err != nil {
+			return model.Responce{}, err
+		}
+		if len(fields) == 0 {
+			return model.Responce{Success: true, Value: nil}, nil
+		}
+		return model.Responce{Success: true, Value: []byte(fields[0].Field)}, nil
+	}
+	count, err := strconv.Atoi(request.Params[1])
+	if err != nil {
+		return model.Responce{}, errs.InvalidIntValue
+	}
+	withValues := false
+	if len(request.Params) > 2 {
+		if len(request.Params) > 3 || !strings.EqualFold(request.Params[2], "WITHVALUES") {
+			return model.Responce{}, errs.SyntaxError
+		}
+		withValues = true
+	}
+	fields, err := rp.DataStore.HRandField(request.Params[0], count)
+	if err != nil {
+		return model.Responce{}, err
+	}
+	if withValues {
+		return model.Responce{Success: true, Value: fields}, nil
+	}
+	names := make([]string, 0, len(fields))
+	for _, field := range fields {
+		names = append(names, field.Field)
+	}
+	return model.Responce{Success: true, Value: names}, nil
+}
+
+func boolToInt(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,115 @@
+package processor
+
+import (
+	"strings"
+
+	"github.com/saurabhy27/redis-database/constants"
+	"github.com/saurabhy27/redis-database/errs"
+	"github.com/saurabhy27/redis-database/model"
+	req "github.com/saurabhy27/redis-database/request"
+)
+
+// acl categories reported for each command flag, on top of the category of
+// the data type
+var flagCategories = map[string][]string{
+	constants.FlagWrite:    {"@write"},
+	constants.FlagReadonly: {"@read"},
+	constants.FlagFast:     {"@fast"},
+	constants.FlagAdmin:    {"@admin", "@dangerous"},
+	constants.FlagBlocking: {"@blocking"},
+}
+
+// processCommand answers COMMAND [COUNT | INFO [name ...] | DOCS [name ...] | LIST]
+func (rp *RequestProcessor) processCommand(request model.Request) (model.Responce, error) {
+	if len(request.Params) == 0 {
+		commands := req.Commands()
+		infos := make([]any, 0, len(commands))
+		for _, command := range commands {
+			infos = append(infos, commandInfo(command))
+		}
+		return model.Responce{Success: true, Value: infos}, nil
+	}
+	names := request.Params[1:]
+	switch strings.ToUpper(request.Params[0]) {
+	case "COUNT":
+		if len(names) != 0 {
+			return model.Responce{}, errs.MinReqParams
+		}
+		return model.Responce{Success: true, Value: len(req.Commands())}, nil
+	case "LIST":
+		if len(names) != 0 {
+			return model.Responce{}, errs.SyntaxError
+		}
+		list := []string{}
+		for _, command := range req.Commands() {
+			list = append(list, strings.ToLower(command.Cmd))
+		}
+		return model.Responce{Success: true, Value: list}, nil
+	case "INFO":
+		commands := lookupCommands(names)
+		infos := make([]any, 0, len(commands))
+		for _, command := range commands {
+			if command.Cmd == "" {
+				// unknown names get a null entry
+				infos = append(infos, nil)
+				continue
+			}
+			infos = append(infos, commandInfo(command))
+		}
+		return model.Responce{Success: true, Value: infos}, nil
+	case "DOCS":
+		docs := model.Map{}
+		for _, command := range lookupCommands(names) {
+			if command.Cmd == "" {
+				continue
+			}
+			docs = append(docs, model.KeyValue{Key: strings.ToLower(command.Cmd), Value: model.Map{
+				{Key: "summary", Value: []byte(command.Summary)},
+			}})
+		}
+		return model.Responce{Success: true, Value: docs}, nil
+	default:
+		return model.Responce{}, errs.UnknownSubcommand
+	}
+}
+
+// lookupCommands returns the commands named, or the whole command table when
+// no name is given. Unknown names give an empty command.
+func lookupCommands(names []string) []model.Command {
+	if len(names) == 0 {
+		return req.Commands()
+	}
+	commands := make([]model.Command, 0, len(names))
+	for _, name := range names {
+		command, _ := req.Lookup(name)
+		commands = append(commands, command)
+	}
+	return commands
+}
+
+// commandInfo describes a command the way redis 7 does: name, arity, flags,
+// first key, last key, key step, acl categories, tips, key specifications
+// and subcommands.
+func commandInfo(command model.Command) []any {
+	flags := model.Set{}
+	categories := model.Set{}
+	if command.Category != "" {
+		categories = append(categories, command.Category)
+	}
+	for _, flag := range command.Flags {
+		flags = append(flags, flag)
+		categories = append(categories, flagCategories[flag]...)
+	}
+	return []any{
+		[]byte(strings.ToLower(command.Cmd)),
+		command.Arity,
+		flags,
+		command.FirstKey,
+		command.LastKey,
+		command.KeyStep,
+		categories,
+		[]string{},
+		[]any{},
+		[]any{},
+	}
+}
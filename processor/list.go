@@ -0,0 +1,238 @@
+package processor
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/saurabhy27/redis-database/errs"
+	"github.com/saurabhy27/redis-database/model"
+)
+
+// processLPush: LPUSH key element [element ...]
+func (rp *RequestProcessor) processLPush(request model.Request) (model.Responce, error) {
+	return rp.pushGeneric(request, true)
+}
+
+// processRPush: RPUSH key element [element ...]
+func (rp *RequestProcessor) processRPush(request model.Request) (model.Responce, error) {
+	return rp.pushGeneric(request, false)
+}
+
+func (rp *RequestProcessor) pushGeneric(request model.Request, left bool) (model.Responce, error) {
+	length, err := rp.DataStore.Push(request.Params[0], request.Params[1:], left)
+	if err != nil {
+		return model.Responce{}, err
+	}
+	return model.Responce{Success: true, Value: length}, nil
+}
+
+// processLPop: LPOP key [count]
+func (rp *RequestProcessor) processLPop(request model.Request) (model.Responce, error) {
+	return rp.popGeneric(request, true)
+}
+
+// processRPop: RPOP key [count]
+func (rp *RequestProcessor) processRPop(request model.Request) (model.Responce, error) {
+	return rp.popGeneric(request, false)
+}
+
+func (rp *RequestProcessor) popGeneric(request model.Request, left bool) (model.Responce, error) {
+	if len(request.Params) > 2 {
+		return model.Responce{}, errs.SyntaxError
+	}
+	count := 1
+	if len(request.Params) == 2 {
+		var err error
+		if count, err = strconv.Atoi(request.Params[1]); err != nil || count < 0 {
+			return model.Responce{}, errs.NegativeCount
+		}
+	}
+	elements, err := rp.DataStore.Pop(request.Params[0], left, count)
+	if err != nil {
+		return model.Responce{}, err
+	}
+	// with a count the elements come as an array, even a single one
+	if len(request.Params) == 2 {
+		if elements == nil {
+			return model.Responce{Success: true, Value: model.NullArray{}}, nil
+		}
+		return model.Responce{Success: true, Value: elements}, nil
+	}
+	if elements == nil {
+		return model.Responce{Success: true, Value: nil}, nil
+	}
+	return model.Responce{Success: true, Value: []byte(elements[0])}, nil
+}
+
+// processLLen: LLEN key
+func (rp *RequestProcessor) processLLen(request model.Request) (model.Responce, error) {
+	length, err := rp.DataStore.LLen(request.Params[0])
+	if err != nil {
+		return model.Responce{}, err
+	}
+	return model.Responce{Success: true, Value: length}, nil
+}
+
+// processLRange: LRANGE key start stop
+func (rp *RequestProcessor) processLRange(request model.Request) (model.Responce, error) {
+	start, err := strconv.Atoi(request.Params[1])
+	if err != nil {
+		return model.Responce{}, errs.InvalidIntValue
+	}
+	stop, err := strconv.Atoi(request.Params[2])
+	if err != nil {
+		return model.Responce{}, errs.InvalidIntValue
+	}
+	elements, err := rp.DataStore.LRange(request.Params[0], start, stop)
+	if err != nil {
+		return model.Responce{}, err
+	}
+	return model.Responce{Success: true, Value: elements}, nil
+}
+
+// processLIndex: LINDEX key index
+func (rp *RequestProcessor) processLIndex(request model.Request) (model.Responce, error) {
+	index, err := strconv.Atoi(request.Params[1])
+	if err != nil {
+		return model.Responce{}, errs.InvalidIntValue
+	}
+	element, err := rp.DataStore.LIndex(request.Params[0], index)
+	if err != nil {
+		return model.Responce{}, err
+	}
+	return model.Responce{Success: true, Value: element}, nil
+}
+
+// processLSet: LSET key index element
+func (rp *RequestProcessor) processLSet(request model.Request) (model.Responce, error) {
+	index, err := strconv.Atoi(request.Params[1])
+	if err != nil {
+		return model.Responce{}, errs.InvalidIntValue
+	}
+	if err := rp.DataStore.LSet(request.Params[0], index, request.Params[2]); err != nil {
+		return model.Responce{}, err
+	}
+	return model.Responce{Success: true, Value: "OK"}, nil
+}
+
+// processLRem: LREM key count element
+func (rp *RequestProcessor) processLRem(request model.Request) (model.Responce, error) {
+	count, err := strconv.Atoi(request.Params[1])
+	if err != nil {
+		return model.Responce{}, errs.InvalidIntValue
+	}
+	removed, err := rp.DataStore.LRem(request.Params[0], count, request.Params[2])
+	if err != nil {
+		return model.Responce{}, err
+	}
+	return model.Responce{Success: true, Value: removed}, nil
+}
+
+// processLTrim: LTRIM key start stop
+func (rp *RequestProcessor) processLTrim(request model.Request) (model.Responce, error) {
+	start, err := strconv.Atoi(request.Params[1])
+	if err != nil {
+		return model.Responce{}, errs.InvalidIntValue
+	}
+	stop, err := strconv.Atoi(request.Params[2])
+	if err != nil {
+		return model.Responce{}, errs.InvalidIntValue
+	}
+	if err := rp.DataStore.LTrim(request.Params[0], start, stop); err != nil {
+		return model.Responce{}, err
+	}
+	return model.Responce{Success: true, Value: "OK"}, nil
+}
+
+// processLInsert: LINSERT key <BEFORE | AFTER> pivot element
+func (rp *RequestProcessor) processLInsert(request model.Request) (model.Responce, error) {
+	var after bool
+	switch strings.ToUpper(request.Params[1]) {
+	case "BEFORE":
+	case "AFTER":
+		after = true
+	default:
+		return model.Responce{}, errs.SyntaxError
+	}
+	length, err := rp.DataStore.LInsert(request.Params[0], after, request.Params[2], request.Params[3])
+	if err != nil {
+		return model.Responce{}, err
+	}
+	return model.Responce{Success: true, Value: length}, nil
+}
+
+// processBLPop: BLPOP key [key ...] timeout
+func (rp *RequestProcessor) processBLPop(request model.Request) (model.Responce, error) {
+	return rp.bpopGeneric(request, true)
+}
+
+// processBRPop: BRPOP key [key ...] timeout
+func (rp *RequestProcessor) processBRPop(request model.Request) (model.Responce, error) {
+	return rp.bpopGeneric(request, false)
+}
+
+func (rp *RequestProcessor) bpopGeneric(request model.Request, left bool) (model.Responce, error) {
+	last := len(request.Params) - 1
+	timeout, err := parseTimeout(request.Params[last])
+	if err != nil {
+		return model.Responce{}, err
+	}
+	key, element, err := rp.DataStore.BPop(request.Params[:last], left, timeout, request.Done)
+	if err != nil {
+		return model.Responce{}, err
+	}
+	if key == "" {
+		return model.Responce{Success: true, Value: model.NullArray{}}, nil
+	}
+	return model.Responce{Success: true, Value: []any{[]byte(key), element}}, nil
+}
+
+// parseListEnd parses LEFT or RIGHT, it returns true for LEFT.
+func parseListEnd(value string) (bool, error) {
+	switch strings.ToUpper(value) {
+	case "LEFT":
+		return true, nil
+	case "RIGHT":
+		return false, nil
+	}
+	return false, errs.SyntaxError
+}
+
+// processLMove: LMOVE source destination <LEFT | RIGHT> <LEFT | RIGHT>
+func (rp *RequestProcessor) processLMove(request model.Request) (model.Responce, error) {
+	srcLeft, err := parseListEnd(request.Params[2])
+	if err != nil {
+		return model.Responce{}, err
+	}
+	dstLeft, err := parseListEnd(request.Params[3])
+	if err != nil {
+		return model.Responce{}, err
+	}
+	element, err := rp.DataStore.LMove(request.Params[0], request.Params[1], srcLeft, dstLeft)
+	if err != nil {
+		return model.Responce{}, err
+	}
+	return model.Responce{Success: true, Value: element}, nil
+}
+
+// processBLMove: BLMOVE source destination <LEFT | RIGHT> <LEFT | RIGHT>
+// timeout
+func (rp *RequestProcessor) processBLMove(request model.Request) (model.Responce, error) {
+	srcLeft, err := parseListEnd(request.Params[2])
+	if err != nil {
+		return model.Responce{}, err
+	}
+	dstLeft, err := parseListEnd(request.Params[3])
+	if err != nil {
+		return model.Responce{}, err
+	}
+	timeout, err := parseTimeout(request.Params[4])
+	if err != nil {
+		return model.Responce{}, err
+	}
+	element, err := rp.DataStore.BLMove(request.Params[0], request.Params[1], srcLeft, dstLeft, timeout, request.Done)
+	if err != nil {
+		return model.Responce{}, err
+	}
+	return model.Responce{Success: true, Value: element}, nil
+}
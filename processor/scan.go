@@ -0,0 +1,130 @@
+package processor
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/saurabhy27/redis-database/errs"
+	"github.com/saurabhy27/redis-database/model"
+	"github.com/saurabhy27/redis-database/utils"
+)
+
+// keys a SCAN call returns when COUNT isn't given, like redis
+const defaultScanCount = 10
+
+type scanOptions struct {
+	pattern string
+	count   int
+	typ     string
+}
+
+// parseCursor parses the cursor argument of the SCAN family.
+func parseCursor(value string) (uint64, error) {
+	cursor, err := strconv.ParseUint(value, 10, 64)
+	if err != nil {
+		return 0, errs.InvalidCursor
+	}
+	return cursor, nil
+}
+
+// parseScanOptions parses [MATCH pattern] [COUNT count], plus [TYPE type]
+// when withType is set.
+func parseScanOptions(params []string, withType bool) (scanOptions, error) {
+	options := scanOptions{pattern: "*", count: defaultScanCount}
+	for i := 0; i < len(params); i += 2 {
+		if i+1 >= len(params) {
+			return options, errs.SyntaxError
+		}
+		value := params[i+1]
+		switch strings.ToUpper(params[i]) {
+		case "MATCH":
+			options.pattern = value
+		case "COUNT":
+			count, err := strconv.Atoi(value)
+			if err != nil {
+				return options, errs.InvalidIntValue
+			}
+			if count < 1 {
+				return options, errs.SyntaxError
+			}
+			options.count = count
+		case "TYPE":
+			if !withType {
+				return options, errs.SyntaxError
+			}
+			options.typ = value
+		default:
+			return options, errs.SyntaxError
+		}
+	}
+	return options, nil
+}
+
+// scanReply builds the two element reply of the SCAN family, the next cursor
+// as a bulk string followed by the batch.
+func scanReply(cursor uint64, batch []string) model.Responce {
+	return model.Responce{Success: true, Value: []any{[]byte(strconv.FormatUint(cursor, 10)), batch}}
+}
+
+// processScan: SCAN cursor [MATCH pattern] [COUNT count] [TYPE type]
+func (rp *RequestProcessor) processScan(request model.Request) (model.Responce, error) {
+	cursor, err := parseCursor(request.Params[0])
+	if err != nil {
+		return model.Responce{}, err
+	}
+	options, err := parseScanOptions(request.Params[1:], true)
+	if err != nil {
+		return model.Responce{}, err
+	}
+	cursor, keys, err := rp.DataStore.Scan(cursor, options.pattern, options.count, options.typ)
+	if err != nil {
+		return model.Responce{}, err
+	}
+	return scanReply(cursor, keys), nil
+}
+
+// processZScan: ZSCAN key cursor [MATCH pattern] [COUNT count]
+func (rp *RequestProcessor) processZScan(request model.Request) (model.Responce, error) {
+	key := request.Params[0]
+	cursor, err := parseCursor(request.Params[1])
+	if err != nil {
+		return model.Responce{}, err
+	}
+	options, err := parseScanOptions(request.Params[2:], false)
+	if err != nil {
+		return model.Responce{}, err
+	}
+	cursor, members, err := rp.DataStore.ZScan(key, cursor, options.pattern, options.count)
+	if err != nil {
+		return model.Responce{}, err
+	}
+	// members and scores alternate as bulk strings, like in redis
+	batch := make([]string, 0, len(members)*2)
+	for _, member := range members {
+		batch = append(batch, member.Member, utils.FormatFloat(member.Score))
+	}
+	return scanReply(cursor, batch), nil
+}
+
+// processHScan: HSCAN key cursor [MATCH pattern] [COUNT count]
+func (rp *RequestProcessor) processHScan(request model.Request) (model.Responce, error) {
+	key := request.Params[0]
+	cursor, err := parseCursor(request.Params[1])
+	if err != nil {
+		return model.Responce{}, err
+	}
+	options, err := parseScanOptions(request.Params[2:], false)
+	if err != nil {
+		return model.Responce{}, err
+	}
+	cursor, fields, err := rp.DataStore.HScan(key, cursor, options.pattern, options.count)
+	if err != nil {
+		return model.Responce{}, err
+	}
+	// fields and values alternate, like in redis
+	batch := make([]string, 0, len(fields)*2)
+	for _, field := range fields {
+		batch = append(batch, field.Field, field.Value)
+	}
+	return scanReply(cursor, batch), nil
+}
@@ -0,0 +1,184 @@
+package processor
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/saurabhy27/redis-database/errs"
+	"github.com/saurabhy27/redis-database/model"
+)
+
+// expireUnits maps the TTL options of SET and GETEX to the unit of their
+// argument in milliseconds and whether it is an absolute unix time.
+var expireUnits = map[string]struct {
+	unit     int64
+	absolute bool
+}{
+	"EX":   {1000, false},
+	"PX":   {1, false},
+	"EXAT": {1000, true},
+	"PXAT": {1, true},
+}
+
+// parseExpireOption turns the argument of an EX, PX, EXAT or PXAT option
+// into a deadline in unix milliseconds.
+func parseExpireOption(option string, value string) (int64, error) {
+	when, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return 0, errs.InvalidIntValue
+	}
+	if when <= 0 {
+		return 0, errs.InvalidExpireTime
+	}
+	expireUnit := expireUnits[option]
+	return toDeadline(when, expireUnit.unit, expireUnit.absolute)
+}
+
+// parseSetOptions parses [NX | XX] [GET] [EX seconds | PX milliseconds |
+// EXAT unix-time-seconds | PXAT unix-time-milliseconds | KEEPTTL]
+func parseSetOptions(params []string) (model.SetOptions, error) {
+	options := model.SetOptions{}
+	hasExpire := false
+	for i := 0; i < len(params); i++ {
+		option := strings.ToUpper(params[i])
+		switch option {
+		case "NX":
+			if options.XX {
+				return options, errs.SyntaxError
+			}
+			options.NX = true
+		case "XX":
+			if options.NX {
+				return options, errs.SyntaxError
+			}
+			options.XX = true
+		case "GET":
+			options.Get = true
+		case "KEEPTTL":
+			if hasExpire {
+				return options, errs.SyntaxError
+			}
+			options.KeepTTL = true
+		case "EX", "PX", "EXAT", "PXAT":
+			if hasExpire || options.KeepTTL || i+1 >= len(params) {
+				return options, errs.SyntaxError
+			}
+			deadline, err := parseExpireOption(option, params[i+1])
+			if err != nil {
+				return options, err
+			}
+			options.Deadline = deadline
+			hasExpire = true
+			i++
+		default:
+			return options, errs.SyntaxError
+		}
+	}
+	return options, nil
+}
+
+// setGeneric runs SET and its variants. Unless the old value is asked for it
+// replies OK, or nil when NX or XX prevented the write.
+func (rp *RequestProcessor) setGeneric(key string, value string, options model.SetOptions) (model.Responce, error) {
+	old, set, err := rp.DataStore.SetWithOptions(key, []byte(value), options)
+	if err != nil {
+		return model.Responce{}, err
+	}
+	if options.Get {
+		return model.Responce{Success: true, Value: old}, nil
+	}
+	if !set {
+		return model.Responce{Success: true, Value: []byte(nil)}, nil
+	}
+	return model.Responce{Success: true, Value: "OK"}, nil
+}
+
+func (rp *RequestProcessor) processGet(request model.Request) (model.Responce, error) {
+	data, err := rp.DataStore.Get(request.Params[0])
+	if err != nil {
+		return model.Responce{}, err
+	}
+	return model.Responce{Success: true, Value: data}, nil
+}
+
+// processSet: SET key value [NX | XX] [GET] [EX seconds | PX milliseconds |
+// EXAT unix-time-seconds | PXAT unix-time-milliseconds | KEEPTTL]
+func (rp *RequestProcessor) processSet(request model.Request) (model.Responce, error) {
+	key := request.Params[0]
+	value := request.Params[1]
+	options, err := parseSetOptions(request.Params[2:])
+	if err != nil {
+		return model.Responce{}, err
+	}
+	return rp.setGeneric(key, value, options)
+}
+
+// processSetEx: SETEX key seconds value
+func (rp *RequestProcessor) processSetEx(request model.Request) (model.Responce, error) {
+	return rp.setExpireGeneric(request, "EX")
+}
+
+// processPSetEx: PSETEX key milliseconds value
+func (rp *RequestProcessor) processPSetEx(request model.Request) (model.Responce, error) {
+	return rp.setExpireGeneric(request, "PX")
+}
+
+func (rp *RequestProcessor) setExpireGeneric(request model.Request, option string) (model.Responce, error) {
+	deadline, err := parseExpireOption(option, request.Params[1])
+	if err != nil {
+		return model.Responce{}, err
+	}
+	return rp.setGeneric(request.Params[0], request.Params[2], model.SetOptions{Deadline: deadline})
+}
+
+// processSetNx: SETNX key value, replies 1 when the key was set
+func (rp *RequestProcessor) processSetNx(request model.Request) (model.Responce, error) {
+	_, set, err := rp.DataStore.SetWithOptions(request.Params[0], []byte(request.Params[1]), model.SetOptions{NX: true})
+	if err != nil {
+		return model.Responce{}, err
+	}
+	if set {
+		return model.Responce{Success: true, Value: 1}, nil
+	}
+	return model.Responce{Success: true, Value: 0}, nil
+}
+
+// processGetSet: GETSET key value
+func (rp *RequestProcessor) processGetSet(request model.Request) (model.Responce, error) {
+	return rp.setGeneric(request.Params[0], request.Params[1], model.SetOptions{Get: true})
+}
+
+// processGetDel: GETDEL key
+func (rp *RequestProcessor) processGetDel(request model.Request) (model.Responce, error) {
+	data, err := rp.DataStore.GetDel(request.Params[0])
+	if err != nil {
+		return model.Responce{}, err
+	}
+	return model.Responce{Success: true, Value: data}, nil
+}
+
+// processGetEx: GETEX key [EX seconds | PX milliseconds |
+// EXAT unix-time-seconds | PXAT unix-time-milliseconds | PERSIST]
+func (rp *RequestProcessor) processGetEx(request model.Request) (model.Responce, error) {
+	params := request.Params[1:]
+	var deadline int64
+	persist := false
+	switch {
+	case len(params) == 0:
+	case len(params) == 1 && strings.ToUpper(params[0]) == "PERSIST":
+		persist = true
+	case len(params) == 2 && expireUnits[strings.ToUpper(params[0])].unit != 0:
+		var err error
+		deadline, err = parseExpireOption(strings.ToUpper(params[0]), params[1])
+		if err != nil {
+			return model.Responce{}, err
+		}
+	default:
+		return model.Responce{}, errs.SyntaxError
+	}
+	data, err := rp.DataStore.GetEx(request.Params[0], deadline, persist)
+	if err != nil {
+		return model.Responce{}, err
+	}
+	return model.Responce{Success: true, Value: data}, nil
+}
@@ -0,0 +1,202 @@
+package processor
+
+import (
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/saurabhy27/redis-database/errs"
+	"github.com/saurabhy27/redis-database/model"
+)
+
+// parseTimeout parses the timeout in seconds of the blocking commands, 0
+// means waiting forever.
+func parseTimeout(value string) (time.Duration, error) {
+	seconds, err := strconv.ParseFloat(value, 64)
+	if err != nil || math.IsNaN(seconds) || math.IsInf(seconds, 0) || seconds > math.MaxInt64/float64(time.Second) {
+		return 0, errs.InvalidTimeout
+	}
+	if seconds < 0 {
+		return 0, errs.NegativeTimeout
+	}
+	return time.Duration(seconds * float64(time.Second)), nil
+}
+
+// memberScorePairs returns every member and its score as a two element
+// array, like ZMPOP replies in both protocols.
+func memberScorePairs(data []model.SortedSet) []any {
+	pairs := make([]any, 0, len(data))
+	for _, member := range data {
+		pairs = append(pairs, []any{[]byte(member.Member), member.Score})
+	}
+	return pairs
+}
+
+// processZPopMin: ZPOPMIN key [count]
+func (rp *RequestProcessor) processZPopMin(request model.Request) (model.Responce, error) {
+	return rp.zpopGeneric(request, false)
+}
+
+// processZPopMax: ZPOPMAX key [count]
+func (rp *RequestProcessor) processZPopMax(request model.Request) (model.Responce, error) {
+	return rp.zpopGeneric(request, true)
+}
+
+func (rp *RequestProcessor) zpopGeneric(request model.Request, max bool) (model.Responce, error) {
+	if len(request.Params) > 2 {
+		return model.Responce{}, errs.SyntaxError
+	}
+	count := 1
+	if len(request.Params) == 2 {
+		var err error
+		if count, err = strconv.Atoi(request.Params[1]); err != nil {
+			return model.Responce{}, errs.InvalidIntValue
+		}
+		if count < 0 {
+			return model.Responce{}, errs.NegativeCount
+		}
+	}
+	_, data, err := rp.DataStore.ZPop(request.Params[:1], max, count)
+	if err != nil {
+		return model.Responce{}, err
+	}
+	if len(request.Params) == 2 {
+		if data == nil {
+			data = []model.SortedSet{}
+		}
+		return model.Responce{Success: true, Value: data}, nil
+	}
+	// a single member comes as a flat member, score array
+	reply := []any{}
+	for _, member := range data {
+		reply = append(reply, []byte(member.Member), member.Score)
+	}
+	return model.Responce{Success: true, Value: reply}, nil
+}
+
+// processBZPopMin: BZPOPMIN key [key ...] timeout
+func (rp *RequestProcessor) processBZPopMin(request model.Request) (model.Responce, error) {
+	return rp.bzpopGeneric(request, false)
+}
+
+// processBZPopMax: BZPOPMAX key [key ...] timeout
+func (rp *RequestProcessor) processBZPopMax(request model.Request) (model.Responce, error) {
+	return rp.bzpopGeneric(request, true)
+}
+
+func (rp *RequestProcessor) bzpopGeneric(request model.Request, max bool) (model.Responce, error) {
+	last := len(request.Params) - 1
+	timeout, err := parseTimeout(request.Params[last])
+	if err != nil {
+		return model.Responce{}, err
+	}
+	key, data, err := rp.DataStore.BZPop(request.Params[:last], max, 1, timeout, request.Done)
+	if err != nil {
+		return model.Responce{}, err
+	}
+	if key == "" {
+		return model.Responce{Success: true, Value: model.NullArray{}}, nil
+	}
+	return model.Responce{Success: true, Value: []any{[]byte(key), []byte(data[0].Member), data[0].Score}}, nil
+}
+
+// parseZMPop parses numkeys key [key ...] <MIN | MAX> [COUNT count].
+func parseZMPop(params []string) ([]string, bool, int, error) {
+	keys, params, err := parseNumKeys(params)
+	if err != nil {
+		return nil, false, 0, err
+	}
+	if len(params) == 0 {
+		return nil, false, 0, errs.SyntaxError
+	}
+	var max bool
+	switch strings.ToUpper(params[0]) {
+	case "MIN":
+	case "MAX":
+		max = true
+	default:
+		return nil, false, 0, errs.SyntaxError
+	}
+	count := 1
+	switch {
+	case len(params) == 3 && strings.EqualFold(params[1], "COUNT"):
+		if count, err = strconv.Atoi(params[2]); err != nil || count <= 0 {
+			return nil, false, 0, errs.CountNotPositive
+		}
+	case len(params) != 1:
+		return nil, false, 0, errs.SyntaxError
+	}
+	return keys, max, count, nil
+}
+
+// processZMPop: ZMPOP numkeys key [key ...] <MIN | MAX> [COUNT count]
+func (rp *RequestProcessor) processZMPop(request model.Request) (model.Responce, error) {
+	keys, max, count, err := parseZMPop(request.Params)
+	if err != nil {
+		return model.Responce{}, err
+	}
+	key, data, err := rp.DataStore.ZPop(keys, max, count)
+	return zmpopReply(key, data, err)
+}
+
+// processBZMPop: BZMPOP timeout numkeys key [key ...] <MIN | MAX> [COUNT count]
+func (rp *RequestProcessor) processBZMPop(request model.Request) (model.Responce, error) {
+	timeout, err := parseTimeout(request.Params[0])
+	if err != nil {
+		return model.Responce{}, err
+	}
+	keys, max, count, err := parseZMPop(request.Params[1:])
+	if err != nil {
+		return model.Responce{}, err
+	}
+	key, data, err := rp.DataStore.BZPop(keys, max, count, timeout, request.Done)
+	return zmpopReply(key, data, err)
+}
+
+func zmpopReply(key string, data []model.SortedSet, err error) (model.Responce, error) {
+	if err != nil {
+		return model.Responce{}, err
+	}
+	if key == "" {
+		return model.Responce{Success: true, Value: model.NullArray{}}, nil
+	}
+	return model.Responce{Success: true, Value: []any{[]byte(key), memberScorePairs(data)}}, nil
+}
+
+// processZRandMember: ZRANDMEMBER key [count [WITHSCORES]]
+func (rp *RequestProcessor) processZRandMember(request model.Request) (model.Responce, error) {
+	if len(request.Params) == 1 {
+		data, err := rp.DataStore.ZRandMember(request.Params[0], 1)
+		if err != nil {
+			return model.Responce{}, err
+		}
+		if len(data) == 0 {
+			return model.Responce{Success: true, Value: nil}, nil
+		}
+		return model.Responce{Success: true, Value: []byte(data[0].Member)}, nil
+	}
+	count, err := strconv.Atoi(request.Params[1])
+	if err != nil {
+		return model.Responce{}, errs.InvalidIntValue
+	}
+	withScores := false
+	if len(request.Params) > 2 {
+		if len(request.Params) > 3 || !strings.EqualFold(request.Params[2], "WITHSCORES") {
+			return model.Responce{}, errs.SyntaxError
+		}
+		withScores = true
+	}
+	data, err := rp.DataStore.ZRandMember(request.Params[0], count)
+	if err != nil {
+		return model.Responce{}, err
+	}
+	if withScores {
+		return model.Responce{Success: true, Value: data}, nil
+	}
+	members := make([]string, 0, len(data))
+	for _, member := range data {
+		members = append(members, member.Member)
+	}
+	return model.Responce{Success: true, Value: members}, nil
+}
@@ -0,0 +1,420 @@
+package processor
+
+import (
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/saurabhy27/redis-database/errs"
+	"github.com/saurabhy27/redis-database/model"
+)
+
+// processZAdd: ZADD key [NX | XX] [GT | LT] [CH] [INCR] score member [score
+// member ...]
+func (rp *RequestProcessor) processZAdd(request model.Request) (model.Responce, error) {
+	key := request.Params[0]
+	options, param := parseZAddOptions(request.Params[1:])
+	if len(param) == 0 || len(param)%2 != 0 {
+		return model.Responce{}, errs.SyntaxError
+	}
+	if options.NX && options.XX {
+		return model.Responce{}, errs.ZAddNXXXIncompatible
+	}
+	if options.GT && options.LT || options.NX && (options.GT || options.LT) {
+		return model.Responce{}, errs.ZAddGTLTNXIncompatible
+	}
+	if options.Incr && len(param) > 2 {
+		return model.Responce{}, errs.ZAddIncrSinglePair
+	}
+	var sorted_set []model.SortedSetByte
+
+	for i := 0; i < len(param); i += 2 {
+		score := param[i]
+		value := param[i+1]
+		scoreFloat, err := strconv.ParseFloat(score, 64)
+		if err != nil || math.IsNaN(scoreFloat) {
+			return model.Responce{}, errs.InvalidFloatValue
+		}
+		sorted_set = append(sorted_set, model.SortedSetByte{Score: scoreFloat, Member: []byte(value)})
+	}
+
+	added, score, err := rp.DataStore.ZAddWithOptions(key, sorted_set, options)
+	if err != nil {
+		return model.Responce{}, err
+	}
+	if !options.Incr {
+		return model.Responce{Success: true, Value: added}, nil
+	}
+	if score == nil {
+		return model.Responce{Success: true, Value: nil}, nil
+	}
+	return model.Responce{Success: true, Value: *score}, nil
+}
+
+// parseZAddOptions reads the flags in front of the score member pairs and
+// returns the pairs.
+func parseZAddOptions(params []string) (model.ZAddOptions, []string) {
+	options := model.ZAddOptions{}
+	for i, param := range params {
+		switch strings.ToUpper(param) {
+		case "NX":
+			options.NX = true
+		case "XX":
+			options.XX = true
+		case "GT":
+			options.GT = true
+		case "LT":
+			options.LT = true
+		case "CH":
+			options.CH = true
+		case "INCR":
+			options.Incr = true
+		default:
+			return options, params[i:]
+		}
+	}
+	return options, nil
+}
+
+// processZRange: ZRANGE key start stop [BYSCORE | BYLEX] [REV] [LIMIT offset
+// count] [WITHSCORES]
+func (rp *RequestProcessor) processZRange(request model.Request) (model.Responce, error) {
+	return rp.zrangeGeneric(request, model.ZRangeByRank, false, true)
+}
+
+// processZRevRange: ZREVRANGE key start stop [WITHSCORES]
+func (rp *RequestProcessor) processZRevRange(request model.Request) (model.Responce, error) {
+	return rp.zrangeGeneric(request, model.ZRangeByRank, true, false)
+}
+
+// processZRangeByScore: ZRANGEBYSCORE key min max [WITHSCORES] [LIMIT offset
+// count]
+func (rp *RequestProcessor) processZRangeByScore(request model.Request) (model.Responce, error) {
+	return rp.zrangeGeneric(request, model.ZRangeByScore, false, false)
+}
+
+// processZRevRangeByScore: ZREVRANGEBYSCORE key max min [WITHSCORES] [LIMIT
+// offset count]
+func (rp *RequestProcessor) processZRevRangeByScore(request model.Request) (model.Responce, error) {
+	return rp.zrangeGeneric(request, model.ZRangeByScore, true, false)
+}
+
+// processZRangeByLex: ZRANGEBYLEX key min max [LIMIT offset count]
+func (rp *RequestProcessor) processZRangeByLex(request model.Request) (model.Responce, error) {
+	return rp.zrangeGeneric(request, model.ZRangeByLex, false, false)
+}
+
+// processZRevRangeByLex: ZREVRANGEBYLEX key max min [LIMIT offset count]
+func (rp *RequestProcessor) processZRevRangeByLex(request model.Request) (model.Responce, error) {
+	return rp.zrangeGeneric(request, model.ZRangeByLex, true, false)
+}
+
+// processZRangeStore: ZRANGESTORE dst src min max [BYSCORE | BYLEX] [REV]
+// [LIMIT offset count]
+func (rp *RequestProcessor) processZRangeStore(request model.Request) (model.Responce, error) {
+	options, err := parseZRangeOptions(request.Params[2:], model.ZRangeByRank, false, true, true)
+	if err != nil {
+		return model.Responce{}, err
+	}
+	stored, err := rp.DataStore.ZRangeStore(request.Params[0], request.Params[1], options.spec)
+	if err != nil {
+		return model.Responce{}, err
+	}
+	return model.Responce{Success: true, Value: stored}, nil
+}
+
+// zrangeGeneric runs the commands reading a range of a sorted set, by and rev
+// are the range type and order of the command.
+func (rp *RequestProcessor) zrangeGeneric(request model.Request, by model.ZRangeBy, rev bool, flexible bool) (model.Responce, error) {
+	options, err := parseZRangeOptions(request.Params[1:], by, rev, flexible, false)
+	if err != nil {
+		return model.Responce{}, err
+	}
+	data, err := rp.DataStore.ZRangeBy(request.Params[0], options.spec)
+	if err != nil {
+		return model.Responce{}, err
+	}
+	if options.withScores {
+		return model.Responce{Success: true, Value: data}, nil
+	}
+	members := make([]string, 0, len(data))
+	for _, member := range data {
+		members = append(members, member.Member)
+	}
+	return model.Responce{Success: true, Value: members}, nil
+}
+
+type zrangeOptions struct {
+	spec       model.ZRangeSpec
+	withScores bool
+}
+
+// parseZRangeOptions parses the bounds of a range and its options. The range
+// type and order are fixed by the command unless flexible, ZRANGE then reads
+// them from BYSCORE, BYLEX and REV. The store form takes no WITHSCORES, the
+// fixed lex forms no WITHSCORES and the fixed rank forms no LIMIT.
+func parseZRangeOptions(params []string, by model.ZRangeBy, rev bool, flexible bool, store bool) (zrangeOptions, error) {
+	options := zrangeOptions{spec: model.ZRangeSpec{Count: -1}}
+	hasLimit := false
+	for i := 2; i < len(params); i++ {
+		option := strings.ToUpper(params[i])
+		switch {
+		case option == "WITHSCORES" && !store && (flexible || by != model.ZRangeByLex):
+			options.withScores = true
+		case option == "LIMIT" && (flexible || by != model.ZRangeByRank) && i+2 < len(params):
+			offset, err := strconv.Atoi(params[i+1])
+			if err != nil {
+				return options, errs.InvalidIntValue
+			}
+			count, err := strconv.Atoi(params[i+2])
+			if err != nil {
+				return options, errs.InvalidIntValue
+			}
+			options.spec.Offset, options.spec.Count = offset, count
+			hasLimit = true
+			i += 2
+		case option == "BYSCORE" && flexible && by == model.ZRangeByRank:
+			by = model.ZRangeByScore
+		case option == "BYLEX" && flexible && by == model.ZRangeByRank:
+			by = model.ZRangeByLex
+		case option == "REV" && flexible:
+			rev = true
+		default:
+			return options, errs.SyntaxError
+		}
+	}
+	if hasLimit && by == model.ZRangeByRank {
+		return options, errs.LimitWithoutBy
+	}
+	if options.withScores && by == model.ZRangeByLex {
+		return options, errs.WithScoresByLex
+	}
+	options.spec.By, options.spec.Rev = by, rev
+	min, max := params[0], params[1]
+	if rev && by != model.ZRangeByRank {
+		// the reverse commands take the highest bound first
+		min, max = max, min
+	}
+	var err error
+	switch by {
+	case model.ZRangeByRank:
+		if options.spec.Start, err = strconv.Atoi(min); err != nil {
+			return options, errs.InvalidIntValue
+		}
+		if options.spec.Stop, err = strconv.Atoi(max); err != nil {
+			return options, errs.InvalidIntValue
+		}
+	case model.ZRangeByScore:
+		options.spec.Score, err = parseScoreRange(min, max)
+	case model.ZRangeByLex:
+		options.spec.Lex, err = parseLexRange(min, max)
+	}
+	return options, err
+}
+
+// parseScoreRange parses score bounds like 1.5, (1.5 for an exclusive bound,
+// -inf and +inf.
+func parseScoreRange(min string, max string) (model.ScoreRange, error) {
+	r := model.ScoreRange{}
+	var err error
+	if r.Min, r.MinExclusive, err = parseScoreBound(min); err != nil {
+		return r, err
+	}
+	r.Max, r.MaxExclusive, err = parseScoreBound(max)
+	return r, err
+}
+
+func parseScoreBound(bound string) (float64, bool, error) {
+	exclusive := strings.HasPrefix(bound, "(")
+	if exclusive {
+		bound = bound[1:]
+	}
+	score, err := strconv.ParseFloat(bound, 64)
+	if err != nil || math.IsNaN(score) {
+		return 0, false, errs.InvalidScoreRange
+	}
+	return score, exclusive, nil
+}
+
+// parseLexRange parses member bounds, [a for an inclusive bound, (a for an
+// exclusive one, - and + for the lowest and highest member.
+func parseLexRange(min string, max string) (model.LexRange, error) {
+	r := model.LexRange{}
+	// + as min or - as max selects nothing, the same as an empty exclusive
+	// range
+	if min == "+" || max == "-" {
+		return model.LexRange{MinExclusive: true}, nil
+	}
+	if min == "-" {
+		r.MinInfinite = true
+	} else if bound, exclusive, ok := parseLexBound(min); ok {
+		r.Min, r.MinExclusive = bound, exclusive
+	} else {
+		return r, errs.InvalidLexRange
+	}
+	if max == "+" {
+		r.MaxInfinite = true
+	} else if bound, exclusive, ok := parseLexBound(max); ok {
+		r.Max, r.MaxExclusive = bound, exclusive
+	} else {
+		return r, errs.InvalidLexRange
+	}
+	return r, nil
+}
+
+func parseLexBound(bound string) (string, bool, bool) {
+	if bound == "" {
+		return "", false, false
+	}
+	switch bound[0] {
+	case '(':
+		return bound[1:], true, true
+	case '[':
+		return bound[1:], false, true
+	default:
+		return "", false, false
+	}
+}
+
+// processZRank: ZRANK key member [WITHSCORE]
+func (rp *RequestProcessor) processZRank(request model.Request) (model.Responce, error) {
+	return rp.zrankGeneric(request, false)
+}
+
+// processZRevRank: ZREVRANK key member [WITHSCORE]
+func (rp *RequestProcessor) processZRevRank(request model.Request) (model.Responce, error) {
+	return rp.zrankGeneric(request, true)
+}
+
+func (rp *RequestProcessor) zrankGeneric(request model.Request, reverse bool) (model.Responce, error) {
+	withScore := false
+	if len(request.Params) > 2 {
+		if len(request.Params) > 3 || !strings.EqualFold(request.Params[2], "WITHSCORE") {
+			return model.Responce{}, errs.SyntaxError
+		}
+		withScore = true
+	}
+	rank, score, err := rp.DataStore.ZRank(request.Params[0], request.Params[1], reverse)
+	if err != nil {
+		return model.Responce{}, err
+	}
+	switch {
+	case rank < 0:
+		return model.Responce{Success: true, Value: nil}, nil
+	case withScore:
+		return model.Responce{Success: true, Value: []any{rank, score}}, nil
+	default:
+		return model.Responce{Success: true, Value: rank}, nil
+	}
+}
+
+// processZScore: ZSCORE key member
+func (rp *RequestProcessor) processZScore(request model.Request) (model.Responce, error) {
+	scores, err := rp.DataStore.ZMScore(request.Params[0], request.Params[1:])
+	if err != nil {
+		return model.Responce{}, err
+	}
+	if scores[0] == nil {
+		return model.Responce{Success: true, Value: nil}, nil
+	}
+	return model.Responce{Success: true, Value: *scores[0]}, nil
+}
+
+// processZMScore: ZMSCORE key member [member ...]
+func (rp *RequestProcessor) processZMScore(request model.Request) (model.Responce, error) {
+	scores, err := rp.DataStore.ZMScore(request.Params[0], request.Params[1:])
+	if err != nil {
+		return model.Responce{}, err
+	}
+	reply := make([]any, len(scores))
+	for i, score := range scores {
+		if score != nil {
+			reply[i] = *score
+		}
+	}
+	return model.Responce{Success: true, Value: reply}, nil
+}
+
+// processZCard: ZCARD key
+func (rp *RequestProcessor) processZCard(request model.Request) (model.Responce, error) {
+	card, err := rp.DataStore.ZCard(request.Params[0])
+	if err != nil {
+		return model.Responce{}, err
+	}
+	return model.Responce{Success: true, Value: card}, nil
+}
+
+// processZCount: ZCOUNT key min max
+func (rp *RequestProcessor) processZCount(request model.Request) (model.Responce, error) {
+	score, err := parseScoreRange(request.Params[1], request.Params[2])
+	if err != nil {
+		return model.Responce{}, err
+	}
+	return rp.zcountGeneric(request.Params[0], model.ZRangeSpec{By: model.ZRangeByScore, Score: score})
+}
+
+// processZLexCount: ZLEXCOUNT key min max
+func (rp *RequestProcessor) processZLexCount(request model.Request) (model.Responce, error) {
+	lex, err := parseLexRange(request.Params[1], request.Params[2])
+	if err != nil {
+		return model.Responce{}, err
+	}
+	return rp.zcountGeneric(request.Params[0], model.ZRangeSpec{By: model.ZRangeByLex, Lex: lex})
+}
+
+func (rp *RequestProcessor) zcountGeneric(key string, spec model.ZRangeSpec) (model.Responce, error) {
+	count, err := rp.DataStore.ZCount(key, spec)
+	if err != nil {
+		return model.Responce{}, err
+	}
+	return model.Responce{Success: true, Value: count}, nil
+}
+
+// processZIncrBy: ZINCRBY key increment member
+func (rp *RequestProcessor) processZIncrBy(request model.Request) (model.Responce, error) {
+	increment, err := strconv.ParseFloat(request.Params[1], 64)
+	if err != nil || math.IsNaN(increment) {
+		return model.Responce{}, errs.InvalidFloatValue
+	}
+	score, err := rp.DataStore.ZIncrBy(request.Params[0], increment, request.Params[2])
+	if err != nil {
+		return model.Responce{}, err
+	}
+	return model.Responce{Success: true, Value: score}, nil
+}
+
+// processZRem: ZREM key member [member ...]
+func (rp *RequestProcessor) processZRem(request model.Request) (model.Responce, error) {
+	removed, err := rp.DataStore.ZRem(request.Params[0], request.Params[1:])
+	if err != nil {
+		return model.Responce{}, err
+	}
+	return model.Responce{Success: true, Value: removed}, nil
+}
+
+// processZRemRangeByRank: ZREMRANGEBYRANK key start stop
+func (rp *RequestProcessor) processZRemRangeByRank(request model.Request) (model.Responce, error) {
+	return rp.zremrangeGeneric(request, model.ZRangeByRank)
+}
+
+// processZRemRangeByScore: ZREMRANGEBYSCORE key min max
+func (rp *RequestProcessor) processZRemRangeByScore(request model.Request) (model.Responce, error) {
+	return rp.zremrangeGeneric(request, model.ZRangeByScore)
+}
+
+// processZRemRangeByLex: ZREMRANGEBYLEX key min max
+func (rp *RequestProcessor) processZRemRangeByLex(request model.Request) (model.Responce, error) {
+	return rp.zremrangeGeneric(request, model.ZRangeByLex)
+}
+
+func (rp *RequestProcessor) zremrangeGeneric(request model.Request, by model.ZRangeBy) (model.Responce, error) {
+	options, err := parseZRangeOptions(request.Params[1:], by, false, false, true)
+	if err != nil {
+		return model.Responce{}, err
+	}
+	removed, err := rp.DataStore.ZRemRangeBy(request.Params[0], options.spec)
+	if err != nil {
+		return model.Responce{}, err
+	}
+	return model.Responce{Success: true, Value: removed}, nil
+}
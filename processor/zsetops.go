@@ -0,0 +1,169 @@
+package processor
+
+import (
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/saurabhy27/redis-database/errs"
+	"github.com/saurabhy27/redis-database/model"
+)
+
+var aggregates = map[string]model.Aggregate{
+	"SUM": model.AggregateSum,
+	"MIN": model.AggregateMin,
+	"MAX": model.AggregateMax,
+}
+
+// parseNumKeys reads numkeys key [key ...] and returns the keys and the
+// arguments after them.
+func parseNumKeys(params []string) ([]string, []string, error) {
+	numKeys, err := strconv.Atoi(params[0])
+	if err != nil {
+		return nil, nil, errs.InvalidIntValue
+	}
+	if numKeys < 1 {
+		return nil, nil, errs.NoInputKeys
+	}
+	if numKeys > len(params)-1 {
+		return nil, nil, errs.SyntaxError
+	}
+	return params[1 : numKeys+1], params[numKeys+1:], nil
+}
+
+type zsetOpOptions struct {
+	options    model.ZSetOpOptions
+	withScores bool
+}
+
+// parseZSetOpOptions parses [WEIGHTS weight [weight ...]] [AGGREGATE SUM | MIN
+// | MAX], taken by every operation but the difference, and [WITHSCORES],
+// taken by every form but the store one.
+func parseZSetOpOptions(params []string, numKeys int, op model.ZSetOp, store bool) (zsetOpOptions, error) {
+	options := zsetOpOptions{}
+	for i := 0; i < len(params); i++ {
+		option := strings.ToUpper(params[i])
+		switch {
+		case option == "WEIGHTS" && op != model.ZSetDiff && i+numKeys < len(params):
+			options.options.Weights = make([]float64, numKeys)
+			for j := range options.options.Weights {
+				weight, err := strconv.ParseFloat(params[i+1+j], 64)
+				if err != nil || math.IsNaN(weight) {
+					return options, errs.InvalidWeight
+				}
+				options.options.Weights[j] = weight
+			}
+			i += numKeys
+		case option == "AGGREGATE" && op != model.ZSetDiff && i+1 < len(params):
+			aggregate, ok := aggregates[strings.ToUpper(params[i+1])]
+			if !ok {
+				return options, errs.SyntaxError
+			}
+			options.options.Aggregate = aggregate
+			i++
+		case option == "WITHSCORES" && !store:
+			options.withScores = true
+		default:
+			return options, errs.SyntaxError
+		}
+	}
+	return options, nil
+}
+
+// processZUnion: ZUNION numkeys key [key ...] [WEIGHTS weight [weight ...]]
+// [AGGREGATE SUM | MIN | MAX] [WITHSCORES]
+func (rp *RequestProcessor) processZUnion(request model.Request) (model.Responce, error) {
+	return rp.zsetOpGeneric(request, model.ZSetUnion)
+}
+
+// processZInter: ZINTER numkeys key [key ...] [WEIGHTS weight [weight ...]]
+// [AGGREGATE SUM | MIN | MAX] [WITHSCORES]
+func (rp *RequestProcessor) processZInter(request model.Request) (model.Responce, error) {
+	return rp.zsetOpGeneric(request, model.ZSetInter)
+}
+
+// processZDiff: ZDIFF numkeys key [key ...] [WITHSCORES]
+func (rp *RequestProcessor) processZDiff(request model.Request) (model.Responce, error) {
+	return rp.zsetOpGeneric(request, model.ZSetDiff)
+}
+
+func (rp *RequestProcessor) zsetOpGeneric(request model.Request, op model.ZSetOp) (model.Responce, error) {
+	keys, params, err := parseNumKeys(request.Params)
+	if err != nil {
+		return model.Responce{}, err
+	}
+	options, err := parseZSetOpOptions(params, len(keys), op, false)
+	if err != nil {
+		return model.Responce{}, err
+	}
+	data, err := rp.DataStore.ZSetOperation(op, keys, options.options)
+	if err != nil {
+		return model.Responce{}, err
+	}
+	if options.withScores {
+		return model.Responce{Success: true, Value: data}, nil
+	}
+	members := make([]string, 0, len(data))
+	for _, member := range data {
+		members = append(members, member.Member)
+	}
+	return model.Responce{Success: true, Value: members}, nil
+}
+
+// processZUnionStore: ZUNIONSTORE destination numkeys key [key ...] [WEIGHTS
+// weight [weight ...]] [AGGREGATE SUM | MIN | MAX]
+func (rp *RequestProcessor) processZUnionStore(request model.Request) (model.Responce, error) {
+	return rp.zsetOpStoreGeneric(request, model.ZSetUnion)
+}
+
+// processZInterStore: ZINTERSTORE destination numkeys key [key ...] [WEIGHTS
+// weight [weight ...]] [AGGREGATE SUM | MIN | MAX]
+func (rp *RequestProcessor) processZInterStore(request model.Request) (model.Responce, error) {
+	return rp.zsetOpStoreGeneric(request, model.ZSetInter)
+}
+
+// processZDiffStore: ZDIFFSTORE destination numkeys key [key ...]
+func (rp *RequestProcessor) processZDiffStore(request model.Request) (model.Responce, error) {
+	return rp.zsetOpStoreGeneric(request, model.ZSetDiff)
+}
+
+func (rp *RequestProcessor) zsetOpStoreGeneric(request model.Request, op model.ZSetOp) (model.Responce, error) {
+	keys, params, err := parseNumKeys(request.Params[1:])
+	if err != nil {
+		return model.Responce{}, err
+	}
+	options, err := parseZSetOpOptions(params, len(keys), op, true)
+	if err != nil {
+		return model.Responce{}, err
+	}
+	stored, err := rp.DataStore.ZSetOperationStore(request.Params[0], op, keys, options.options)
+	if err != nil {
+		return model.Responce{}, err
+	}
+	return model.Responce{Success: true, Value: stored}, nil
+}
+
+// processZInterCard: ZINTERCARD numkeys key [key ...] [LIMIT limit]
+func (rp *RequestProcessor) processZInterCard(request model.Request) (model.Responce, error) {
+	keys, params, err := parseNumKeys(request.Params)
+	if err != nil {
+		return model.Responce{}, err
+	}
+	limit := 0
+	for i := 0; i < len(params); i += 2 {
+		if !strings.EqualFold(params[i], "LIMIT") || i+1 >= len(params) {
+			return model.Responce{}, errs.SyntaxError
+		}
+		if limit, err = strconv.Atoi(params[i+1]); err != nil {
+			return model.Responce{}, errs.InvalidIntValue
+		}
+		if limit < 0 {
+			return model.Responce{}, errs.NegativeLimit
+		}
+	}
+	card, err := rp.DataStore.ZInterCard(keys, limit)
+	if err != nil {
+		return model.Responce{}, err
+	}
+	return model.Responce{Success: true, Value: card}, nil
+}
@@ -0,0 +1,39 @@
+package request
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/saurabhy27/redis-database/model"
+)
+
+// commands is the command table, keyed by the upper case command name. The
+// packages executing the commands register them at init time.
+var commands = map[string]model.Command{}
+
+// Register adds a command to the command table. Registering the same name
+// twice is a programming error and panics.
+func Register(command model.Command) {
+	command.Cmd = strings.ToUpper(command.Cmd)
+	if _, ok := commands[command.Cmd]; ok {
+		panic(fmt.Sprintf("command %s registered twice", command.Cmd))
+	}
+	commands[command.Cmd] = command
+}
+
+// Lookup finds a command by its case insensitive name.
+func Lookup(name string) (model.Command, bool) {
+	command, ok := commands[strings.ToUpper(name)]
+	return command, ok
+}
+
+// Commands returns the whole command table sorted by name.
+func Commands() []model.Command {
+	all := make([]model.Command, 0, len(commands))
+	for _, command := range commands {
+		all = append(all, command)
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i].Cmd < all[j].Cmd })
+	return all
+}
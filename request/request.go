@@ -3,57 +3,137 @@ package request
 import (
 	"strings"
 
-	"github.com/saurabhy27/redis-database/constants"
 	"github.com/saurabhy27/redis-database/errs"
 	"github.com/saurabhy27/redis-database/model"
 )
 
-var (
-	CMDGet    = model.Command{Cmd: constants.GET, MinReqParams: 1}
-	CMDDel    = model.Command{Cmd: constants.DEL, MinReqParams: 1}
-	CMDExpire = model.Command{Cmd: constants.EXPIRE, MinReqParams: 2}
-	CMDKeys   = model.Command{Cmd: constants.KEYS, MinReqParams: 1}
-	CMDSet    = model.Command{Cmd: constants.SET, MinReqParams: 2}
-	CMDTtl    = model.Command{Cmd: constants.TTL, MinReqParams: 1}
-	CMDZAdd   = model.Command{Cmd: constants.ZADD, MinReqParams: 3}
-	CMDZRange = model.Command{Cmd: constants.ZRANGE, MinReqParams: 3}
-)
+// ParseProtocol parses an inline command typed into a terminal.
+func ParseProtocol(input string) (model.Request, error) {
+	args, err := SplitArgs(input)
+	if err != nil {
+		return model.Request{}, err
+	}
+	return ParseArgs(args)
+}
+
+// SplitArgs splits an inline command into the command name and arguments the
+// way redis does. Arguments are separated by any amount of white space and
+// can be quoted: double quotes understand \n, \r, \t, \b, \a and \xHH
+// escapes, single quotes only \'. A closing quote must be followed by white
+// space or the end of the line.
+func SplitArgs(input string) ([]string, error) {
+	args := []string{}
+	i := 0
+	for {
+		for i < len(input) && isSpace(input[i]) {
+			i++
+		}
+		if i == len(input) {
+			return args, nil
+		}
+		var arg strings.Builder
+		inDoubleQuotes, inSingleQuotes := false, false
+		for done := false; !done; i++ {
+			if i == len(input) {
+				if inDoubleQuotes || inSingleQuotes {
+					return nil, errs.UnbalancedQuotes
+				}
+				break
+			}
+			c := input[i]
+			switch {
+			case inDoubleQuotes:
+				if c == '\\' && i+3 < len(input) && input[i+1] == 'x' && isHexDigit(input[i+2]) && isHexDigit(input[i+3]) {
+					arg.WriteByte(hexValue(input[i+2])<<4 | hexValue(input[i+3]))
+					i += 3
+				} else if c == '\\' && i+1 < len(input) {
+					i++
+					arg.WriteByte(unescape(input[i]))
+				} else if c == '"' {
+					if i+1 < len(input) && !isSpace(input[i+1]) {
+						return nil, errs.UnbalancedQuotes
+					}
+					done = true
+				} else {
+					arg.WriteByte(c)
+				}
+			case inSingleQuotes:
+				if c == '\\' && i+1 < len(input) && input[i+1] == '\'' {
+					i++
+					arg.WriteByte('\'')
+				} else if c == '\'' {
+					if i+1 < len(input) && !isSpace(input[i+1]) {
+						return nil, errs.UnbalancedQuotes
+					}
+					done = true
+				} else {
+					arg.WriteByte(c)
+				}
+			case isSpace(c):
+				done = true
+			case c == '"':
+				inDoubleQuotes = true
+			case c == '\'':
+				inSingleQuotes = true
+			default:
+				arg.WriteByte(c)
+			}
+		}
+		args = append(args, arg.String())
+	}
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\v' || c == '\f'
+}
+
+func isHexDigit(c byte) bool {
+	return (c >= '0' && c <= '9') || (c >= 'a' && c <= 'f') || (c >= 'A' && c <= 'F')
+}
 
-func parseCommand(cmd string) (model.Command, error) {
-	switch cmd {
-	case constants.GET:
-		return CMDGet, nil
-	case constants.DEL:
-		return CMDDel, nil
-	case constants.EXPIRE:
-		return CMDExpire, nil
-	case constants.KEYS:
-		return CMDKeys, nil
-	case constants.SET:
-		return CMDSet, nil
-	case constants.TTL:
-		return CMDTtl, nil
-	case constants.ZADD:
-		return CMDZAdd, nil
-	case constants.ZRANGE:
-		return CMDZRange, nil
+func hexValue(c byte) byte {
+	switch {
+	case c >= 'a':
+		return c - 'a' + 10
+	case c >= 'A':
+		return c - 'A' + 10
 	default:
-		return model.Command{}, errs.InvalidCommand
+		return c - '0'
 	}
 }
 
-func ParseProtocol(input string) (model.Request, error) {
-	input_splited := strings.Split(input, " ")
-	if len(input_splited) == 0 {
+// unescape returns the character a backslash escape inside double quotes
+// stands for, any other character is taken literally.
+func unescape(c byte) byte {
+	switch c {
+	case 'n':
+		return '\n'
+	case 'r':
+		return '\r'
+	case 't':
+		return '\t'
+	case 'b':
+		return '\b'
+	case 'a':
+		return '\a'
+	default:
+		return c
+	}
+}
+
+// ParseArgs builds a request from a command already split into its name and
+// arguments, as decoded from RESP. The command is looked up in the command
+// table and its arity checked.
+func ParseArgs(args []string) (model.Request, error) {
+	if len(args) == 0 {
 		return model.Request{}, errs.EmptyRequest
 	}
-	command, err := parseCommand(input_splited[0])
-	if err != nil {
+	command, ok := Lookup(args[0])
+	if !ok {
 		return model.Request{}, errs.InvalidCommand
 	}
-	params := input_splited[1:]
-	if len(params) < command.MinReqParams {
+	if (command.Arity > 0 && len(args) != command.Arity) || len(args) < -command.Arity {
 		return model.Request{}, errs.MinReqParams
 	}
-	return model.Request{Command: command, Params: params}, nil
+	return model.Request{Command: command, Params: args[1:]}, nil
 }
@@ -0,0 +1,217 @@
+package resp
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/saurabhy27/redis-database/errs"
+)
+
+const (
+	// size of the read buffer kept for every connection
+	readBufSize = 16 * 1024
+	// longest inline command accepted, same as redis
+	maxInlineSize = 64 * 1024
+	// most arguments a single command can have
+	maxMultiBulkLen = 1024 * 1024
+	// bulk strings larger than this are read in chunks instead of being
+	// allocated upfront from the length sent by the client
+	bulkChunkSize = 64 * 1024
+)
+
+// returned by readLine, callers turn it into the matching protocol error
+var errLineTooLong = errors.New("line too long")
+
+var protocolErrors = []error{
+	errs.InvalidMultiBulkLength,
+	errs.InvalidBulkLength,
+	errs.ExpectedBulkString,
+	errs.TooBigInlineRequest,
+	errs.UnbalancedQuotes,
+}
+
+// IsProtocolError reports whether err means the stream is out of sync and the
+// connection has to be closed after replying.
+func IsProtocolError(err error) bool {
+	for _, protoErr := range protocolErrors {
+		if errors.Is(err, protoErr) {
+			return true
+		}
+	}
+	return false
+}
+
+// Reader assembles complete commands from a stream, no matter how the bytes
+// were split across reads.
+type Reader struct {
+	rd         *bufio.Reader
+	maxBulkLen int
+}
+
+func NewReader(rd io.Reader, maxBulkLen int) *Reader {
+	return &Reader{rd: bufio.NewReaderSize(rd, readBufSize), maxBulkLen: maxBulkLen}
+}
+
+// Wait blocks until the stream has input beyond what is already buffered, or
+// reading fails. Nothing is consumed, so calling it in a loop watches a
+// connection for a disconnect even behind pipelined commands. It returns
+// bufio.ErrBufferFull once the buffer can't hold more input.
+func (r *Reader) Wait() error {
+	_, err := r.rd.Peek(r.rd.Buffered() + 1)
+	return err
+}
+
+// HasCommand reports whether a whole command is already buffered, so reading
+// it won't wait for more input from the client. Malformed input counts as a
+// command since reading it fails straight away.
+func (r *Reader) HasCommand() bool {
+	buf, _ := r.rd.Peek(r.rd.Buffered())
+	line, rest, ok := cutLine(buf)
+	if !ok {
+		return false
+	}
+	if buf[0] != Array {
+		return true
+	}
+	count, err := strconv.Atoi(string(line[1:]))
+	if err != nil {
+		return true
+	}
+	for i := 0; i < count; i++ {
+		if line, rest, ok = cutLine(rest); !ok {
+			return false
+		}
+		if len(line) == 0 || line[0] != BulkString {
+			return true
+		}
+		size, err := strconv.Atoi(string(line[1:]))
+		if err != nil || size < 0 {
+			return true
+		}
+		if len(rest) < size+2 {
+			return false
+		}
+		rest = rest[size+2:]
+	}
+	return true
+}
+
+// cutLine splits b after the first \n and returns the line without its
+// terminator, ok is false when b holds no complete line.
+func cutLine(b []byte) (line []byte, rest []byte, ok bool) {
+	i := bytes.IndexByte(b, '\n')
+	if i < 0 {
+		return nil, b, false
+	}
+	return bytes.TrimSuffix(b[:i], []byte("\r")), b[i+1:], true
+}
+
+// IsMultiBulk waits for the next command and reports whether it is a RESP
+// array, which is how redis clients send their commands. Anything else is an
+// inline command.
+func (r *Reader) IsMultiBulk() (bool, error) {
+	b, err := r.rd.Peek(1)
+	if err != nil {
+		return false, err
+	}
+	return b[0] == Array, nil
+}
+
+// ReadInline reads an inline command up to the end of the line, without the
+// line terminator.
+func (r *Reader) ReadInline() (string, error) {
+	line, err := r.readLine(maxInlineSize)
+	if err == errLineTooLong {
+		return "", errs.TooBigInlineRequest
+	}
+	return string(line), err
+}
+
+// ReadMultiBulk reads a RESP array of bulk strings and returns the command
+// name followed by its arguments.
+func (r *Reader) ReadMultiBulk() ([]string, error) {
+	line, err := r.readLine(readBufSize)
+	if err == errLineTooLong {
+		return nil, errs.InvalidMultiBulkLength
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(line) == 0 || line[0] != Array {
+		return nil, errs.InvalidMultiBulkLength
+	}
+	count, err := strconv.Atoi(string(line[1:]))
+	if err != nil || count > maxMultiBulkLen {
+		return nil, errs.InvalidMultiBulkLength
+	}
+	if count <= 0 {
+		return []string{}, nil
+	}
+	args := make([]string, 0, min(count, 1024))
+	for i := 0; i < count; i++ {
+		arg, err := r.readBulkString()
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, arg)
+	}
+	return args, nil
+}
+
+func (r *Reader) readBulkString() (string, error) {
+	line, err := r.readLine(readBufSize)
+	if err == errLineTooLong {
+		return "", errs.InvalidBulkLength
+	}
+	if err != nil {
+		return "", err
+	}
+	if len(line) == 0 || line[0] != BulkString {
+		return "", errs.ExpectedBulkString
+	}
+	size, err := strconv.Atoi(string(line[1:]))
+	if err != nil || size < 0 || size > r.maxBulkLen {
+		return "", errs.InvalidBulkLength
+	}
+	var arg strings.Builder
+	arg.Grow(min(size, bulkChunkSize))
+	if _, err := io.CopyN(&arg, r.rd, int64(size)); err != nil {
+		return "", err
+	}
+	crlf := make([]byte, 2)
+	if _, err := io.ReadFull(r.rd, crlf); err != nil {
+		return "", err
+	}
+	if crlf[0] != '\r' || crlf[1] != '\n' {
+		return "", errs.InvalidBulkLength
+	}
+	return arg.String(), nil
+}
+
+// readLine reads up to the next \n, strips the \r\n or \n terminator and
+// fails with errLineTooLong once the line grows past limit.
+func (r *Reader) readLine(limit int) ([]byte, error) {
+	var line []byte
+	for {
+		chunk, err := r.rd.ReadSlice('\n')
+		if len(line)+len(chunk) > limit {
+			return nil, errLineTooLong
+		}
+		line = append(line, chunk...)
+		if err == bufio.ErrBufferFull {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		line = line[:len(line)-1]
+		if n := len(line); n > 0 && line[n-1] == '\r' {
+			line = line[:n-1]
+		}
+		return line, nil
+	}
+}
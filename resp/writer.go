@@ -0,0 +1,209 @@
+package resp
+
+import (
+	"log"
+	"strconv"
+	"strings"
+
+	"github.com/saurabhy27/redis-database/model"
+	"github.com/saurabhy27/redis-database/utils"
+)
+
+// protocol versions a client can negotiate with HELLO
+const (
+	RESP2 = 2
+	RESP3 = 3
+)
+
+// RESP2 type prefixes
+const (
+	SimpleString = '+'
+	Error        = '-'
+	Integer      = ':'
+	BulkString   = '$'
+	Array        = '*'
+)
+
+// RESP3 only type prefixes
+const (
+	Null   = '_'
+	Double = ','
+	Map    = '%'
+	Set    = '~'
+)
+
+// error codes that are already part of the message and must not get the
+// generic ERR prefix
+var errorCodes = []string{"WRONGTYPE", "NOPROTO"}
+
+func AppendSimpleString(b []byte, s string) []byte {
+	b = append(b, SimpleString)
+	b = append(b, s...)
+	return append(b, '\r', '\n')
+}
+
+func AppendError(b []byte, err error) []byte {
+	msg := err.Error()
+	code, _, _ := strings.Cut(msg, " ")
+	b = append(b, Error)
+	if !utils.Contains(errorCodes, code) {
+		b = append(b, "ERR "...)
+	}
+	b = append(b, msg...)
+	return append(b, '\r', '\n')
+}
+
+func AppendInt(b []byte, n int64) []byte {
+	b = append(b, Integer)
+	b = strconv.AppendInt(b, n, 10)
+	return append(b, '\r', '\n')
+}
+
+func AppendBulkString(b []byte, s []byte) []byte {
+	b = append(b, BulkString)
+	b = strconv.AppendInt(b, int64(len(s)), 10)
+	b = append(b, '\r', '\n')
+	b = append(b, s...)
+	return append(b, '\r', '\n')
+}
+
+// AppendDouble writes a RESP3 double, or a bulk string for RESP2 clients.
+func AppendDouble(b []byte, f float64, proto int) []byte {
+	if proto < RESP3 {
+		return AppendBulkString(b, []byte(utils.FormatFloat(f)))
+	}
+	b = append(b, Double)
+	b = append(b, utils.FormatFloat(f)...)
+	return append(b, '\r', '\n')
+}
+
+// AppendNull writes the RESP3 null, or the null bulk string for RESP2
+// clients.
+func AppendNull(b []byte, proto int) []byte {
+	if proto < RESP3 {
+		return append(b, "$-1\r\n"...)
+	}
+	return append(b, Null, '\r', '\n')
+}
+
+// AppendNullArray writes the RESP3 null, or the null array for RESP2 clients.
+func AppendNullArray(b []byte, proto int) []byte {
+	if proto < RESP3 {
+		return append(b, "*-1\r\n"...)
+	}
+	return append(b, Null, '\r', '\n')
+}
+
+func AppendArrayLen(b []byte, n int) []byte {
+	return appendLen(b, Array, n)
+}
+
+// AppendMapLen starts a RESP3 map of n pairs, or a flat array of 2*n
+// elements for RESP2 clients.
+func AppendMapLen(b []byte, n int, proto int) []byte {
+	if proto < RESP3 {
+		return appendLen(b, Array, n*2)
+	}
+	return appendLen(b, Map, n)
+}
+
+// AppendSetLen starts a RESP3 set, or an array for RESP2 clients.
+func AppendSetLen(b []byte, n int, proto int) []byte {
+	if proto < RESP3 {
+		return appendLen(b, Array, n)
+	}
+	return appendLen(b, Set, n)
+}
+
+func appendLen(b []byte, prefix byte, n int) []byte {
+	b = append(b, prefix)
+	b = strconv.AppendInt(b, int64(n), 10)
+	return append(b, '\r', '\n')
+}
+
+// AppendValue encodes a response value returned by the request processor in
+// the given protocol version. Plain strings are status replies, byte slices
+// are bulk strings and a nil byte slice is the null reply for a missing key.
+func AppendValue(b []byte, value any, proto int) []byte {
+	switch v := value.(type) {
+	case nil:
+		return AppendNull(b, proto)
+	case model.NullArray:
+		return AppendNullArray(b, proto)
+	case error:
+		return AppendError(b, v)
+	case string:
+		return AppendSimpleString(b, v)
+	case int:
+		return AppendInt(b, int64(v))
+	case int64:
+		return AppendInt(b, v)
+	case float64:
+		return AppendDouble(b, v, proto)
+	case []byte:
+		if v == nil {
+			return AppendNull(b, proto)
+		}
+		return AppendBulkString(b, v)
+	case []string:
+		b = AppendArrayLen(b, len(v))
+		for _, s := range v {
+			b = AppendBulkString(b, []byte(s))
+		}
+		return b
+	case []any:
+		b = AppendArrayLen(b, len(v))
+		for _, item := range v {
+			b = AppendValue(b, item, proto)
+		}
+		return b
+	case []model.SortedSet:
+		// RESP3 clients get member score pairs with real doubles, RESP2
+		// clients the flat member, score list
+		if proto < RESP3 {
+			b = AppendArrayLen(b, len(v)*2)
+		} else {
+			b = AppendArrayLen(b, len(v))
+		}
+		for _, sortedSet := range v {
+			if proto >= RESP3 {
+				b = AppendArrayLen(b, 2)
+			}
+			b = AppendBulkString(b, []byte(sortedSet.Member))
+			b = AppendDouble(b, sortedSet.Score, proto)
+		}
+		return b
+	case []model.FieldValue:
+		// like sorted sets, field value pairs for RESP3 clients and a flat
+		// field, value list for RESP2 clients
+		if proto < RESP3 {
+			b = AppendArrayLen(b, len(v)*2)
+		} else {
+			b = AppendArrayLen(b, len(v))
+		}
+		for _, fieldValue := range v {
+			if proto >= RESP3 {
+				b = AppendArrayLen(b, 2)
+			}
+			b = AppendBulkString(b, []byte(fieldValue.Field))
+			b = AppendBulkString(b, []byte(fieldValue.Value))
+		}
+		return b
+	case model.Map:
+		b = AppendMapLen(b, len(v), proto)
+		for _, kv := range v {
+			b = AppendBulkString(b, []byte(kv.Key))
+			b = AppendValue(b, kv.Value, proto)
+		}
+		return b
+	case model.Set:
+		b = AppendSetLen(b, len(v), proto)
+		for _, s := range v {
+			b = AppendSimpleString(b, s)
+		}
+		return b
+	default:
+		log.Println("Type is unknown!")
+		return AppendNull(b, proto)
+	}
+}
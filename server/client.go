@@ -0,0 +1,43 @@
+package server
+
+import (
+	"bufio"
+	"net"
+	"sync/atomic"
+
+	"github.com/saurabhy27/redis-database/resp"
+)
+
+// connections that never sent a RESP command get the plain text replies
+const protoText = 0
+
+var lastClientID atomic.Int64
+
+// client holds the per connection state.
+type client struct {
+	id     int64
+	conn   net.Conn
+	reader *resp.Reader
+	writer *bufio.Writer // replies are buffered until the pipeline is drained
+	proto  int           // protoText, resp.RESP2 or resp.RESP3
+	name   string
+	quit   bool // close the connection without replying to the last command
+}
+
+func newClient(conn net.Conn, maxBulkLen int) *client {
+	return &client{
+		id:     lastClientID.Add(1),
+		conn:   conn,
+		reader: resp.NewReader(conn, maxBulkLen),
+		writer: bufio.NewWriter(conn),
+		proto:  protoText,
+	}
+}
+
+// usesRESP switches a text connection to RESP2 once it sends a RESP command,
+// which is what every redis client does before any HELLO.
+func (c *client) usesRESP() {
+	if c.proto == protoText {
+		c.proto = resp.RESP2
+	}
+}
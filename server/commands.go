@@ -0,0 +1,118 @@
+package server
+
+import (
+	"log"
+	"strconv"
+	"strings"
+
+	"github.com/saurabhy27/redis-database/constants"
+	"github.com/saurabhy27/redis-database/errs"
+	"github.com/saurabhy27/redis-database/model"
+	req "github.com/saurabhy27/redis-database/request"
+	"github.com/saurabhy27/redis-database/resp"
+)
+
+type handler func(s *Server, c *client, request model.Request) (model.Responce, error)
+
+type commandEntry struct {
+	command model.Command
+	handler handler
+}
+
+// commandTable holds the commands that act on the connection or the server
+// itself rather than on the datastore.
+var commandTable = []commandEntry{
+	{
+		command: model.Command{Cmd: constants.HELLO, Arity: -1,
+			Flags:    []string{constants.FlagFast},
+			Category: constants.CategoryConnection,
+			Summary:  "Handshakes with the server and switches the protocol version."},
+		handler: (*Server).processHello,
+	},
+	{
+		command: model.Command{Cmd: constants.SHUTDOWN, Arity: -1,
+			Flags:   []string{constants.FlagAdmin},
+			Summary: "Stops the server after the running commands finished."},
+		handler: (*Server).processShutdown,
+	},
+}
+
+var handlers = map[string]handler{}
+
+func init() {
+	for _, entry := range commandTable {
+		req.Register(entry.command)
+		handlers[entry.command.Cmd] = entry.handler
+	}
+}
+
+// processHello negotiates the protocol version of the connection.
+// HELLO [protover [AUTH username password] [SETNAME clientname]]
+func (s *Server) processHello(c *client, request model.Request) (model.Responce, error) {
+	params := request.Params
+	proto := c.proto
+	if proto == protoText {
+		proto = resp.RESP2
+	}
+	if len(params) > 0 {
+		version, err := strconv.Atoi(params[0])
+		if err != nil {
+			return model.Responce{}, errs.InvalidProtoVersion
+		}
+		if version != resp.RESP2 && version != resp.RESP3 {
+			return model.Responce{}, errs.NoProto
+		}
+		proto = version
+		params = params[1:]
+	}
+	name := c.name
+	for i := 0; i < len(params); i++ {
+		switch strings.ToUpper(params[i]) {
+		case "AUTH":
+			// there are no users configured, every credential is accepted
+			if i+2 >= len(params) {
+				return model.Responce{}, errs.SyntaxError
+			}
+			i += 2
+		case "SETNAME":
+			if i+1 >= len(params) {
+				return model.Responce{}, errs.SyntaxError
+			}
+			name = params[i+1]
+			i++
+		default:
+			return model.Responce{}, errs.SyntaxError
+		}
+	}
+	c.proto = proto
+	c.name = name
+	return model.Responce{Success: true, Value: model.Map{
+		{Key: "server", Value: []byte("redis")},
+		{Key: "version", Value: []byte(constants.Version)},
+		{Key: "proto", Value: proto},
+		{Key: "id", Value: c.id},
+		{Key: "mode", Value: []byte("standalone")},
+		{Key: "role", Value: []byte("master")},
+		{Key: "modules", Value: []string{}},
+	}}, nil
+}
+
+// processShutdown stops the server. Like redis the connection is closed
+// without a reply. There is no persistence, so SAVE and NOSAVE are only
+// validated.
+// SHUTDOWN [NOSAVE|SAVE]
+func (s *Server) processShutdown(c *client, request model.Request) (model.Responce, error) {
+	if len(request.Params) > 1 {
+		return model.Responce{}, errs.SyntaxError
+	}
+	if len(request.Params) == 1 {
+		mode := strings.ToUpper(request.Params[0])
+		if mode != "SAVE" && mode != "NOSAVE" {
+			return model.Responce{}, errs.SyntaxError
+		}
+	}
+	log.Printf("Shutdown requested by client %d\n", c.id)
+	s.stop()
+	c.quit = true
+	return model.Responce{Success: true, Value: "OK"}, nil
+}
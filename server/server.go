@@ -1,113 +1,395 @@
 package server
 
 import (
-	"bytes"
+	"bufio"
+	"context"
+	"errors"
 	"fmt"
 	"log"
 	"net"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/saurabhy27/redis-database/constants"
 	"github.com/saurabhy27/redis-database/model"
 	"github.com/saurabhy27/redis-database/processor"
-	request "github.com/saurabhy27/redis-database/request"
+	req "github.com/saurabhy27/redis-database/request"
+	"github.com/saurabhy27/redis-database/resp"
+	"github.com/saurabhy27/redis-database/utils"
 )
 
 type ServerArgs struct {
-	Port int
+	Port       int
+	MaxBulkLen int // largest bulk string accepted from a client
+	// how long running commands get to finish on shutdown before their
+	// connection is closed, 0 picks the default and a negative value closes
+	// them right away
+	ShutdownTimeout time.Duration
 }
 
 type Server struct {
 	args             ServerArgs
 	requestProcessor processor.RequestProcessorInterface
+	listener         net.Listener
+
+	lock     sync.Mutex           // guards clients
+	clients  map[*client]struct{} // open connections
+	conns    sync.WaitGroup       // running connection handlers
+	stopOnce sync.Once
+	stopping chan struct{} // closed once the shutdown started
+	stopped  chan struct{} // closed once every connection is drained
 }
 
+// New creates a server, zero values in args fall back to the defaults.
 func New(args ServerArgs, requestProcessor processor.RequestProcessorInterface) *Server {
-	return &Server{args: args, requestProcessor: requestProcessor}
+	if args.MaxBulkLen == 0 {
+		args.MaxBulkLen = constants.ProtoMaxBulkLen
+	}
+	if args.ShutdownTimeout == 0 {
+		args.ShutdownTimeout = time.Duration(constants.ShutdownTimeout) * time.Second
+	}
+	return &Server{
+		args:             args,
+		requestProcessor: requestProcessor,
+		clients:          make(map[*client]struct{}),
+		stopping:         make(chan struct{}),
+		stopped:          make(chan struct{}),
+	}
 }
 
-func (s *Server) Start() {
+// Start serves connections until ctx is cancelled or a client sends
+// SHUTDOWN, then drains the open connections before returning.
+func (s *Server) Start(ctx context.Context) {
 	log.Println("Starting connections....")
+	if err := s.Listen(); err != nil {
+		log.Fatalln(err)
+		return
+	}
+	select {
+	case <-ctx.Done():
+		s.Close()
+	case <-s.stopped:
+	}
+}
+
+// Listen binds the port and serves connections in the background. Port 0
+// picks a free port, Addr reports the one actually bound.
+func (s *Server) Listen() error {
 	listener, err := net.Listen("tcp", fmt.Sprintf(":%d", s.args.Port))
 	if err != nil {
-		log.Fatalln(fmt.Errorf("FAILED TO LISTEN TO ADDRESS :%d: %w", s.args.Port, err))
-		return
+		return fmt.Errorf("FAILED TO LISTEN TO ADDRESS :%d: %w", s.args.Port, err)
 	}
-	defer listener.Close()
+	s.listener = listener
+	go s.serve()
+	return nil
+}
+
+// Addr returns the address the server listens on, nil before Listen.
+func (s *Server) Addr() net.Addr {
+	if s.listener == nil {
+		return nil
+	}
+	return s.listener.Addr()
+}
+
+// Close stops accepting connections and returns once the running commands
+// finished and every connection is closed.
+func (s *Server) Close() error {
+	s.stop()
+	if s.listener != nil {
+		<-s.stopped
+	}
+	return nil
+}
+
+// stop starts the shutdown, it is safe to call more than once.
+func (s *Server) stop() {
+	s.stopOnce.Do(func() {
+		close(s.stopping)
+		if s.listener != nil {
+			s.listener.Close()
+		}
+	})
+}
+
+func (s *Server) isClosing() bool {
+	select {
+	case <-s.stopping:
+		return true
+	default:
+		return false
+	}
+}
+
+func (s *Server) serve() {
+	defer close(s.stopped)
 	for {
-		conn, err := listener.Accept()
+		conn, err := s.listener.Accept()
 		if err != nil {
+			if s.isClosing() {
+				break
+			}
 			log.Println(fmt.Errorf("FAILED TO GET CONNECTION: %w", err))
 			continue
 		}
+		s.conns.Add(1)
 		go s.handleConnection(conn)
 	}
+	s.drain()
+}
+
+// drain lets the running commands finish and closes every connection. Idle
+// clients are woken up from their blocking read straight away, clients still
+// busy after ShutdownTimeout are disconnected, their command still runs to
+// the end before drain returns.
+func (s *Server) drain() {
+	log.Println("Shutting down, draining connections....")
+	s.lock.Lock()
+	for c := range s.clients {
+		c.conn.SetReadDeadline(time.Now())
+	}
+	s.lock.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		s.conns.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(s.args.ShutdownTimeout):
+		log.Println("Shutdown timeout reached, closing remaining connections")
+		s.lock.Lock()
+		for c := range s.clients {
+			c.conn.Close()
+		}
+		s.lock.Unlock()
+		<-done
+	}
+	log.Println("Server stopped")
+}
+
+// register tracks a new connection, it fails once the shutdown started.
+func (s *Server) register(c *client) bool {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if s.isClosing() {
+		return false
+	}
+	s.clients[c] = struct{}{}
+	return true
+}
+
+func (s *Server) unregister(c *client) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	delete(s.clients, c)
 }
 
 func (s *Server) handleConnection(conn net.Conn) {
+	defer s.conns.Done()
 	defer conn.Close()
 	log.Println("Connection Created")
+	c := newClient(conn, s.args.MaxBulkLen)
+	if !s.register(c) {
+		return
+	}
+	defer s.unregister(c)
 	for {
-		conn.Write([]byte("redis> "))
-		buf := make([]byte, constants.ArgBufSize)
-		n, err := conn.Read(buf)
+		args, err := s.readArgs(c)
 		if err != nil {
-			log.Println(fmt.Errorf("UNABLE TO READ DATA FROM TERMINAL: %w", err))
+			if s.isClosing() {
+				return
+			}
+			if !resp.IsProtocolError(err) {
+				log.Println(fmt.Errorf("UNABLE TO READ DATA FROM TERMINAL: %w", err))
+				return
+			}
+			// the stream can't be trusted after a framing error
+			log.Println(fmt.Errorf("FAILED TO READ INPUT: %w", err))
+			s.writeError(err, c)
+			c.writer.Flush()
 			return
 		}
-		data := buf[:n]
-		// removing the \n from the end of the string
-		if bytes.HasSuffix(data, []byte("\n")) {
-			data = data[:len(data)-1]
+		if len(args) > 0 {
+			s.handleCommand(c, args)
 		}
-		log.Printf("Received %d bytes: %s\n", n, data)
-
-		request, err := request.ParseProtocol(string(data))
-		if err != nil {
-			log.Println(fmt.Errorf("FAILED TO PARSE INPUT: %w", err))
-			s.writeError(err, conn)
-			continue
+		if c.quit || s.isClosing() {
+			// no new command is started once the shutdown began
+			c.writer.Flush()
+			return
 		}
-		response, err := s.requestProcessor.Process(request)
-		if err != nil {
-			log.Println(fmt.Errorf("FAILED TO EXECUTE THE REQUEST: %w", err))
-			s.writeError(err, conn)
+		// pipelined commands already received are run before the replies
+		// go out together, a partial command waits for its rest only once
+		// the replies so far are sent
+		if c.reader.HasCommand() {
 			continue
 		}
-		s.writeSuccess(response.Value, conn)
+		if c.proto == protoText {
+			c.writer.WriteString("redis> ")
+		}
+		if err := c.writer.Flush(); err != nil {
+			log.Println(fmt.Errorf("UNABLE TO WRITE DATA TO TERMINAL: %w", err))
+			return
+		}
 	}
 }
 
-func (s *Server) writeError(err error, conn net.Conn) {
-	conn.Write([]byte(fmt.Sprintf("ERR %s\n", err)))
+// readArgs reads the next RESP array sent by redis clients, or the inline
+// command typed into a terminal, and returns the command name followed by its
+// arguments.
+func (s *Server) readArgs(c *client) ([]string, error) {
+	multiBulk, err := c.reader.IsMultiBulk()
+	if err != nil {
+		return nil, err
+	}
+	if multiBulk {
+		c.usesRESP()
+		return c.reader.ReadMultiBulk()
+	}
+	line, err := c.reader.ReadInline()
+	if err != nil {
+		return nil, err
+	}
+	log.Printf("Received inline command: %q\n", line)
+	return req.SplitArgs(line)
 }
 
-func (s *Server) writeSuccess(value any, conn net.Conn) {
+func (s *Server) handleCommand(c *client, args []string) {
+	request, err := req.ParseArgs(args)
+	if err != nil {
+		log.Println(fmt.Errorf("FAILED TO PARSE INPUT: %w", err))
+		s.writeError(err, c)
+		return
+	}
+	response, err := s.execute(c, request)
+	if err != nil {
+		log.Println(fmt.Errorf("FAILED TO EXECUTE THE REQUEST: %w", err))
+		s.writeError(err, c)
+		return
+	}
+	if c.quit {
+		return
+	}
+	s.writeSuccess(response.Value, c)
+}
+
+// execute runs the connection level commands itself and hands everything
+// else to the request processor.
+func (s *Server) execute(c *client, request model.Request) (model.Responce, error) {
+	if handler, ok := handlers[request.Command.Cmd]; ok {
+		return handler(s, c, request)
+	}
+	if slices.Contains(request.Command.Flags, constants.FlagBlocking) {
+		return s.executeBlocking(c, request)
+	}
+	return s.requestProcessor.Process(request)
+}
+
+// executeBlocking runs a command that may wait for another client, watching
+// the connection meanwhile. The command stops waiting when the client
+// disconnects or the server shuts down, drain wakes the watcher with a read
+// deadline, so nothing is handed to a client that can't receive it anymore.
+func (s *Server) executeBlocking(c *client, request model.Request) (model.Responce, error) {
+	done := make(chan struct{})
+	var finished atomic.Bool
+	watcher := make(chan struct{})
+	go func() {
+		defer close(watcher)
+		// input pipelined behind the command is buffered but not run before
+		// it is done, the watcher keeps reading past it to see a disconnect
+		for {
+			err := c.reader.Wait()
+			if err == nil {
+				continue
+			}
+			if !errors.Is(err, bufio.ErrBufferFull) && !finished.Load() {
+				close(done)
+			}
+			return
+		}
+	}()
+	request.Done = done
+	response, err := s.requestProcessor.Process(request)
+	finished.Store(true)
+	c.conn.SetReadDeadline(time.Now())
+	<-watcher
+	c.conn.SetReadDeadline(time.Time{})
+	return response, err
+}
+
+func (s *Server) writeError(err error, c *client) {
+	if c.proto == protoText {
+		c.writer.WriteString(fmt.Sprintf("ERR %s\n", err))
+		return
+	}
+	c.writer.Write(resp.AppendError(nil, err))
+}
+
+func (s *Server) writeSuccess(value any, c *client) {
+	if c.proto == protoText {
+		c.writer.Write(appendText(nil, value))
+		return
+	}
+	c.writer.Write(resp.AppendValue(nil, value, c.proto))
+}
+
+// appendText formats a value for connections using the plain text protocol.
+func appendText(b []byte, value any) []byte {
 	switch v := value.(type) {
-	case int:
-		conn.Write([]byte(fmt.Sprintf("%d\n", v)))
+	case int, int64:
+		return fmt.Appendf(b, "%d\n", v)
+	case float64:
+		return fmt.Appendf(b, "%s\n", utils.FormatFloat(v))
 	case string:
-		conn.Write([]byte(fmt.Sprintf("%s\n", v)))
+		return fmt.Appendf(b, "%s\n", v)
 	case []string:
 		for _, s := range v {
-			conn.Write([]byte(fmt.Sprintf("%v\n", s)))
+			b = fmt.Appendf(b, "%v\n", s)
 		}
+		return b
+	case []any:
+		for _, item := range v {
+			b = appendText(b, item)
+		}
+		return b
 	case map[float64]string:
 		for k, v := range v {
-			conn.Write([]byte(fmt.Sprintf("%v  %f\n", v, k)))
+			b = fmt.Appendf(b, "%v  %f\n", v, k)
 		}
+		return b
 	case []model.SortedSet:
 		for _, sortedSet := range v {
-			conn.Write([]byte(fmt.Sprintf("%v  %f\n", sortedSet.Member, sortedSet.Score)))
+			b = fmt.Appendf(b, "%v  %f\n", sortedSet.Member, sortedSet.Score)
+		}
+		return b
+	case model.Map:
+		for _, kv := range v {
+			b = fmt.Appendf(b, "%s\n", kv.Key)
+			b = appendText(b, kv.Value)
+		}
+		return b
+	case []model.FieldValue:
+		for _, fv := range v {
+			b = fmt.Appendf(b, "%s\n%s\n", fv.Field, fv.Value)
+		}
+		return b
+	case model.Set:
+		for _, s := range v {
+			b = fmt.Appendf(b, "%v\n", s)
 		}
-	case nil:
-		conn.Write([]byte("(nil)\n"))
+		return b
+	case nil, model.NullArray:
+		return append(b, "(nil)\n"...)
 	case []byte:
 		if len(v) == 0 {
-			conn.Write([]byte("(nil)\n"))
-		} else {
-			conn.Write([]byte(fmt.Sprintf("%s\n", v)))
+			return append(b, "(nil)\n"...)
 		}
+		return fmt.Appendf(b, "%s\n", v)
 	default:
 		log.Println("Type is unknown!")
+		return b
 	}
 }
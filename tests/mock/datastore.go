@@ -1,6 +1,10 @@
 package mock
 
-import "github.com/saurabhy27/redis-database/model"
+import (
+	"time"
+
+	"github.com/saurabhy27/redis-database/model"
+)
 
 type MockDataStore struct {
 	GetMocked    bool
@@ -11,6 +15,8 @@ type MockDataStore struct {
 	TtlMocked    bool
 	ZAddMocked   bool
 	ZRangeMocked bool
+	ZScanMocked  bool
+	ZRankMocked  bool
 }
 
 func (md *MockDataStore) Get(key string) ([]byte, error) {
@@ -28,26 +34,244 @@ func (mds *MockDataStore) Expire(key string, seconds int) int {
 	return 1
 }
 
-func (mds *MockDataStore) Keys(key string) ([]string, error) {
+func (mds *MockDataStore) PExpireAt(key string, deadline int64, condition model.ExpireCondition) int {
+	mds.ExpireMocked = true
+	return 1
+}
+
+func (mds *MockDataStore) Persist(key string) int {
+	return 0
+}
+
+func (mds *MockDataStore) Keys(pattern string) ([]string, error) {
 	mds.KeysMocked = true
 	return []string{"test", "care"}, nil
 }
 
+func (mds *MockDataStore) Scan(cursor uint64, pattern string, count int, typ string) (uint64, []string, error) {
+	return 0, nil, nil
+}
+
 func (mds *MockDataStore) Set(key string, value []byte) {
 	mds.SetMocked = true
 }
 
+func (mds *MockDataStore) SetWithOptions(key string, value []byte, options model.SetOptions) ([]byte, bool, error) {
+	mds.SetMocked = true
+	return []byte("old123"), true, nil
+}
+
+func (mds *MockDataStore) GetDel(key string) ([]byte, error) {
+	return nil, nil
+}
+
+func (mds *MockDataStore) GetEx(key string, deadline int64, persist bool) ([]byte, error) {
+	return nil, nil
+}
+
 func (mds *MockDataStore) Ttl(key string) int {
 	mds.TtlMocked = true
 	return 1
 }
 
+func (mds *MockDataStore) PTtl(key string) int64 {
+	mds.TtlMocked = true
+	return 1000
+}
+
+func (mds *MockDataStore) ExpireTime(key string) int64 {
+	return 0
+}
+
 func (mds *MockDataStore) ZAdd(key string, sorted_set []model.SortedSetByte) (int, error) {
 	mds.ZAddMocked = true
 	return 2, nil
 }
 
+func (mds *MockDataStore) ZAddWithOptions(key string, sorted_set []model.SortedSetByte, options model.ZAddOptions) (int, *float64, error) {
+	mds.ZAddMocked = true
+	if options.Incr {
+		score := 1.5 + sorted_set[0].Score
+		return 0, &score, nil
+	}
+	return 2, nil, nil
+}
+
 func (mds *MockDataStore) ZRange(key string, start int, stop int) ([]model.SortedSet, error) {
 	mds.ZRangeMocked = true
 	return []model.SortedSet{{Score: 1, Member: "test123"}}, nil
 }
+
+func (mds *MockDataStore) ZRangeBy(key string, spec model.ZRangeSpec) ([]model.SortedSet, error) {
+	mds.ZRangeMocked = true
+	return []model.SortedSet{{Score: 1, Member: "test123"}}, nil
+}
+
+func (mds *MockDataStore) ZRangeStore(dst string, src string, spec model.ZRangeSpec) (int, error) {
+	return 0, nil
+}
+
+func (mds *MockDataStore) ZScan(key string, cursor uint64, pattern string, count int) (uint64, []model.SortedSet, error) {
+	mds.ZScanMocked = true
+	return 0, []model.SortedSet{{Score: 1.5, Member: "test123"}}, nil
+}
+
+func (mds *MockDataStore) ZRank(key string, member string, reverse bool) (int, float64, error) {
+	mds.ZRankMocked = true
+	if member == "missing" {
+		return -1, 0, nil
+	}
+	return 3, 1.5, nil
+}
+
+func (mds *MockDataStore) ZMScore(key string, members []string) ([]*float64, error) {
+	return nil, nil
+}
+
+func (mds *MockDataStore) ZCard(key string) (int, error) {
+	return 0, nil
+}
+
+func (mds *MockDataStore) ZCount(key string, spec model.ZRangeSpec) (int, error) {
+	return 0, nil
+}
+
+func (mds *MockDataStore) ZIncrBy(key string, increment float64, member string) (float64, error) {
+	return 0, nil
+}
+
+func (mds *MockDataStore) ZRem(key string, members []string) (int, error) {
+	return 0, nil
+}
+
+func (mds *MockDataStore) ZRemRangeBy(key string, spec model.ZRangeSpec) (int, error) {
+	return 0, nil
+}
+
+func (mds *MockDataStore) ZSetOperation(op model.ZSetOp, keys []string, options model.ZSetOpOptions) ([]model.SortedSet, error) {
+	return nil, nil
+}
+
+func (mds *MockDataStore) ZSetOperationStore(dst string, op model.ZSetOp, keys []string, options model.ZSetOpOptions) (int, error) {
+	return 0, nil
+}
+
+func (mds *MockDataStore) ZInterCard(keys []string, limit int) (int, error) {
+	return 0, nil
+}
+
+func (mds *MockDataStore) ZPop(keys []string, max bool, count int) (string, []model.SortedSet, error) {
+	return "", nil, nil
+}
+
+func (mds *MockDataStore) BZPop(keys []string, max bool, count int, timeout time.Duration, done <-chan struct{}) (string, []model.SortedSet, error) {
+	return "", nil, nil
+}
+
+func (mds *MockDataStore) ZRandMember(key string, count int) ([]model.SortedSet, error) {
+	return nil, nil
+}
+
+func (mds *MockDataStore) Push(key string, elements []string, left bool) (int, error) {
+	return 0, nil
+}
+
+func (mds *MockDataStore) Pop(key string, left bool, count int) ([]string, error) {
+	return nil, nil
+}
+
+func (mds *MockDataStore) BPop(keys []string, left bool, timeout time.Duration, done <-chan struct{}) (string, []byte, error) {
+	return "", nil, nil
+}
+
+func (mds *MockDataStore) LMove(src string, dst string, srcLeft bool, dstLeft bool) ([]byte, error) {
+	return nil, nil
+}
+
+func (mds *MockDataStore) BLMove(src string, dst string, srcLeft bool, dstLeft bool, timeout time.Duration, done <-chan struct{}) ([]byte, error) {
+	return nil, nil
+}
+
+func (mds *MockDataStore) LLen(key string) (int, error) {
+	return 0, nil
+}
+
+func (mds *MockDataStore) LRange(key string, start int, stop int) ([]string, error) {
+	return nil, nil
+}
+
+func (mds *MockDataStore) LIndex(key string, index int) ([]byte, error) {
+	return nil, nil
+}
+
+func (mds *MockDataStore) LSet(key string, index int, element string) error {
+	return nil
+}
+
+func (mds *MockDataStore) LRem(key string, count int, element string) (int, error) {
+	return 0, nil
+}
+
+func (mds *MockDataStore) LTrim(key string, start int, stop int) error {
+	return nil
+}
+
+func (mds *MockDataStore) LInsert(key string, after bool, pivot string, element string) (int, error) {
+	return 0, nil
+}
+
+func (mds *MockDataStore) HSet(key string, fields []model.FieldValue) (int, error) {
+	return 0, nil
+}
+
+func (mds *MockDataStore) HSetNX(key string, field string, value string) (bool, error) {
+	return false, nil
+}
+
+func (mds *MockDataStore) HGet(key string, field string) ([]byte, error) {
+	return nil, nil
+}
+
+func (mds *MockDataStore) HMGet(key string, fields []string) ([][]byte, error) {
+	return nil, nil
+}
+
+func (mds *MockDataStore) HGetAll(key string) ([]model.FieldValue, error) {
+	return nil, nil
+}
+
+func (mds *MockDataStore) HDel(key string, fields []string) (int, error) {
+	return 0, nil
+}
+
+func (mds *MockDataStore) HExists(key string, field string) (bool, error) {
+	return false, nil
+}
+
+func (mds *MockDataStore) HLen(key string) (int, error) {
+	return 0, nil
+}
+
+func (mds *MockDataStore) HStrLen(key string, field string) (int, error) {
+	return 0, nil
+}
+
+func (mds *MockDataStore) HKeys(key string, values bool) ([]string, error) {
+	return nil, nil
+}
+
+func (mds *MockDataStore) HIncrBy(key string, field string, increment int64) (int64, error) {
+	return 0, nil
+}
+
+func (mds *MockDataStore) HIncrByFloat(key string, field string, increment float64) (float64, error) {
+	return 0, nil
+}
+
+func (mds *MockDataStore) HRandField(key string, count int) ([]model.FieldValue, error) {
+	return nil, nil
+}
+
+func (mds *MockDataStore) HScan(key string, cursor uint64, pattern string, count int) (uint64, []model.FieldValue, error) {
+	return 0, nil, nil
+}
@@ -1,10 +1,14 @@
 package unittest
 
 import (
+	"fmt"
+	"math"
+	"slices"
 	"testing"
 	"time"
 
 	"github.com/saurabhy27/redis-database/datastore"
+	"github.com/saurabhy27/redis-database/errs"
 	"github.com/saurabhy27/redis-database/model"
 	"github.com/saurabhy27/redis-database/utils"
 )
@@ -58,7 +62,7 @@ func TestKeys(t *testing.T) {
 	key2, expVal2 := "care", []byte("care123")
 	dsStore.Set(key1, expVal1)
 	dsStore.Set(key2, expVal2)
-	actKeys, err := dsStore.Keys("\\\\*")
+	actKeys, err := dsStore.Keys("*")
 	if err != nil {
 		t.Errorf("Expected err to be nil, got %v", err)
 	}
@@ -79,7 +83,7 @@ func TestExpire(t *testing.T) {
 	key2, expVal2 := "care", []byte("care123")
 	dsStore.Set(key1, expVal1)
 	dsStore.Set(key2, expVal2)
-	actKeys, _ := dsStore.Keys("\\\\*")
+	actKeys, _ := dsStore.Keys("*")
 	if len(actKeys) != 2 {
 		t.Errorf("Expected keys to be 2, got %d", len(actKeys))
 	}
@@ -88,7 +92,7 @@ func TestExpire(t *testing.T) {
 		t.Errorf("Expected expire value to be %v, got %d", 1, actExp)
 	}
 	time.Sleep(2 * time.Second)
-	actKeys, _ = dsStore.Keys("\\\\*")
+	actKeys, _ = dsStore.Keys("*")
 	if utils.Contains(actKeys, key1) {
 		t.Errorf("Expected %s not to be %v", key1, actKeys)
 	}
@@ -167,3 +171,899 @@ func TestTtl(t *testing.T) {
 		t.Errorf("Expected err to be none -1, got %d", val)
 	}
 }
+
+func TestExpireReplacesTTL(t *testing.T) {
+	dsStore := datastore.New()
+	key1, expVal1 := "test", []byte("test123")
+	key2, expVal2 := "care", []byte("care123")
+	dsStore.Set(key1, expVal1)
+	dsStore.Set(key2, expVal2)
+	dsStore.Expire(key1, 1)
+	dsStore.Expire(key1, 100)
+	dsStore.Expire(key2, 1)
+	// a new value discards the TTL of the old one
+	dsStore.Set(key2, expVal2)
+	if val := dsStore.Ttl(key2); val != -1 {
+		t.Errorf("Expected ttl to be -1, got %d", val)
+	}
+	time.Sleep(2 * time.Second)
+	actKeys, _ := dsStore.Keys("*")
+	if len(actKeys) != 2 {
+		t.Errorf("Expected keys to be 2, got %v", actKeys)
+	}
+}
+
+func TestTtlMissingKey(t *testing.T) {
+	dsStore := datastore.New()
+	if val := dsStore.Ttl("test"); val != -2 {
+		t.Errorf("Expected ttl to be -2, got %d", val)
+	}
+	if val := dsStore.PTtl("test"); val != -2 {
+		t.Errorf("Expected pttl to be -2, got %d", val)
+	}
+}
+
+func TestPExpireAtConditions(t *testing.T) {
+	dsStore := datastore.New()
+	key := "test"
+	dsStore.Set(key, []byte("test123"))
+	now := time.Now().UnixMilli()
+	cases := []struct {
+		deadline  int64
+		condition model.ExpireCondition
+		exp       int
+	}{
+		{now + 10000, model.ExpireXX, 0},
+		{now + 10000, model.ExpireGT, 0},
+		{now + 10000, model.ExpireNX, 1},
+		{now + 20000, model.ExpireNX, 0},
+		{now + 5000, model.ExpireGT, 0},
+		{now + 20000, model.ExpireGT, 1},
+		{now + 30000, model.ExpireLT, 0},
+		{now + 1500, model.ExpireLT | model.ExpireXX, 1},
+	}
+	for i, c := range cases {
+		if act := dsStore.PExpireAt(key, c.deadline, c.condition); act != c.exp {
+			t.Errorf("Expected case %d to return %d, got %d", i, c.exp, act)
+		}
+	}
+	if val := dsStore.PTtl(key); val <= 0 || val > 1500 {
+		t.Errorf("Expected pttl to be at most 1500, got %d", val)
+	}
+	if val := dsStore.ExpireTime(key); val != now+1500 {
+		t.Errorf("Expected expire time to be %d, got %d", now+1500, val)
+	}
+	if val := dsStore.Persist(key); val != 1 {
+		t.Errorf("Expected persist to be 1, got %d", val)
+	}
+	if val := dsStore.Ttl(key); val != -1 {
+		t.Errorf("Expected ttl to be -1, got %d", val)
+	}
+	// a deadline in the past deletes the key
+	dsStore.PExpireAt(key, now-1, model.ExpireAlways)
+	if val, _ := dsStore.Get(key); val != nil {
+		t.Errorf("Expected val to be nil, got %s", string(val))
+	}
+}
+
+func TestSetWithOptions(t *testing.T) {
+	dsStore := datastore.New()
+	key := "lock"
+	_, set, _ := dsStore.SetWithOptions(key, []byte("a"), model.SetOptions{XX: true})
+	if set {
+		t.Errorf("Expected XX not to set a missing key")
+	}
+	deadline := time.Now().UnixMilli() + 30000
+	_, set, _ = dsStore.SetWithOptions(key, []byte("a"), model.SetOptions{NX: true, Deadline: deadline})
+	if !set {
+		t.Errorf("Expected NX to set a missing key")
+	}
+	old, set, _ := dsStore.SetWithOptions(key, []byte("b"), model.SetOptions{NX: true, Get: true})
+	if set || string(old) != "a" {
+		t.Errorf("Expected NX GET to return a without setting, got %s %v", old, set)
+	}
+	dsStore.SetWithOptions(key, []byte("c"), model.SetOptions{KeepTTL: true})
+	if val := dsStore.ExpireTime(key); val != deadline {
+		t.Errorf("Expected KEEPTTL to keep %d, got %d", deadline, val)
+	}
+	dsStore.SetWithOptions(key, []byte("d"), model.SetOptions{})
+	if val := dsStore.Ttl(key); val != -1 {
+		t.Errorf("Expected SET to discard the ttl, got %d", val)
+	}
+	dsStore.ZAdd("zset", []model.SortedSetByte{{Score: 1, Member: []byte("a")}})
+	if _, _, err := dsStore.SetWithOptions("zset", []byte("a"), model.SetOptions{Get: true}); err != errs.WrongType {
+		t.Errorf("Expected err to be %v, got %v", errs.WrongType, err)
+	}
+}
+
+func TestGetDelGetEx(t *testing.T) {
+	dsStore := datastore.New()
+	key := "test"
+	dsStore.Set(key, []byte("test123"))
+	val, _ := dsStore.GetEx(key, time.Now().UnixMilli()+10000, false)
+	if string(val) != "test123" {
+		t.Errorf("Expected val to be test123, got %s", val)
+	}
+	if ttl := dsStore.Ttl(key); ttl != 10 {
+		t.Errorf("Expected ttl to be 10, got %d", ttl)
+	}
+	dsStore.GetEx(key, 0, true)
+	if ttl := dsStore.Ttl(key); ttl != -1 {
+		t.Errorf("Expected ttl to be -1, got %d", ttl)
+	}
+	val, _ = dsStore.GetDel(key)
+	if string(val) != "test123" {
+		t.Errorf("Expected val to be test123, got %s", val)
+	}
+	if val, _ := dsStore.Get(key); val != nil {
+		t.Errorf("Expected val to be nil, got %s", val)
+	}
+}
+
+func TestKeysPattern(t *testing.T) {
+	dsStore := datastore.New()
+	for _, key := range []string{"user:1", "user:2", "xuser:3", "(invalid"} {
+		dsStore.Set(key, []byte("test123"))
+	}
+	actKeys, _ := dsStore.Keys("user:*")
+	if len(actKeys) != 2 || !utils.Contains(actKeys, "user:1") || !utils.Contains(actKeys, "user:2") {
+		t.Errorf("Expected keys to be user:1 and user:2, got %v", actKeys)
+	}
+	actKeys, _ = dsStore.Keys("(*")
+	if len(actKeys) != 1 {
+		t.Errorf("Expected keys to be (invalid, got %v", actKeys)
+	}
+}
+
+func TestScan(t *testing.T) {
+	dsStore := datastore.New()
+	for i := 0; i < 1000; i++ {
+		dsStore.Set(fmt.Sprintf("key:%d", i), []byte("test123"))
+	}
+	// keys added and removed while scanning resize the keyspace both ways,
+	// the keys present the whole time must still all be returned
+	seen := map[string]bool{}
+	cursor, calls := uint64(0), 0
+	for {
+		var keys []string
+		cursor, keys, _ = dsStore.Scan(cursor, "*", 10, "")
+		for _, key := range keys {
+			seen[key] = true
+		}
+		if calls < 500 {
+			dsStore.Set(fmt.Sprintf("added:%d", calls), []byte("test123"))
+			dsStore.Delete(fmt.Sprintf("key:%d", 500+calls))
+		}
+		calls++
+		if cursor == 0 {
+			break
+		}
+	}
+	for i := 0; i < 500; i++ {
+		if !seen[fmt.Sprintf("key:%d", i)] {
+			t.Errorf("Expected key:%d to be returned by scan", i)
+		}
+	}
+}
+
+func TestScanFilters(t *testing.T) {
+	dsStore := datastore.New()
+	dsStore.Set("user:1", []byte("test123"))
+	dsStore.Set("other", []byte("test123"))
+	dsStore.ZAdd("user:zset", []model.SortedSetByte{{Score: 1, Member: []byte("test123")}})
+	var found []string
+	cursor := uint64(0)
+	for {
+		var keys []string
+		cursor, keys, _ = dsStore.Scan(cursor, "user:*", 1, "string")
+		found = append(found, keys...)
+		if cursor == 0 {
+			break
+		}
+	}
+	if len(found) != 1 || found[0] != "user:1" {
+		t.Errorf("Expected keys to be user:1, got %v", found)
+	}
+}
+
+func TestZScan(t *testing.T) {
+	dsStore := datastore.New()
+	for i := 0; i < 100; i++ {
+		dsStore.ZAdd("zset", []model.SortedSetByte{{Score: float64(i - 50), Member: []byte(fmt.Sprintf("member:%d", i))}})
+	}
+	seen := map[string]bool{}
+	cursor, calls := uint64(0), 0
+	for {
+		var members []model.SortedSet
+		cursor, members, _ = dsStore.ZScan("zset", cursor, "member:*", 7)
+		for _, member := range members {
+			seen[member.Member] = true
+		}
+		// members added during the iteration don't shift the cursor
+		dsStore.ZAdd("zset", []model.SortedSetByte{{Score: float64(-100 - calls), Member: []byte("added")}})
+		calls++
+		if cursor == 0 {
+			break
+		}
+	}
+	if len(seen) != 100 {
+		t.Errorf("Expected 100 members to be returned, got %d", len(seen))
+	}
+	dsStore.Set("string", []byte("test123"))
+	_, _, err := dsStore.ZScan("string", 0, "*", 10)
+	if err != errs.WrongType {
+		t.Errorf("Expected err to be %v, got %v", errs.WrongType, err)
+	}
+}
+
+func TestZAddUniqueMembers(t *testing.T) {
+	dsStore := datastore.New()
+	added, _ := dsStore.ZAdd("zset", []model.SortedSetByte{
+		{Score: 1, Member: []byte("b")},
+		{Score: 1, Member: []byte("a")},
+		{Score: 2, Member: []byte("c")},
+	})
+	if added != 3 {
+		t.Errorf("Expected added to be 3, got %d", added)
+	}
+	// c moves to the front instead of being added twice
+	added, _ = dsStore.ZAdd("zset", []model.SortedSetByte{{Score: 0.5, Member: []byte("c")}, {Score: 3, Member: []byte("d")}})
+	if added != 1 {
+		t.Errorf("Expected added to be 1, got %d", added)
+	}
+	actVal, _ := dsStore.ZRange("zset", 0, -1)
+	expected := []model.SortedSet{{Score: 0.5, Member: "c"}, {Score: 1, Member: "a"}, {Score: 1, Member: "b"}, {Score: 3, Member: "d"}}
+	if len(actVal) != len(expected) {
+		t.Fatalf("Expected range to be %v, got %v", expected, actVal)
+	}
+	for i := range expected {
+		if actVal[i] != expected[i] {
+			t.Errorf("Expected range to be %v, got %v", expected, actVal)
+			break
+		}
+	}
+	dsStore.Set("string", []byte("test123"))
+	if _, err := dsStore.ZAdd("string", []model.SortedSetByte{{Score: 1, Member: []byte("a")}}); err != errs.WrongType {
+		t.Errorf("Expected err to be %v, got %v", errs.WrongType, err)
+	}
+}
+
+func TestZRankAndDeepRange(t *testing.T) {
+	dsStore := datastore.New()
+	for i := 0; i < 1000; i++ {
+		dsStore.ZAdd("zset", []model.SortedSetByte{{Score: float64(i % 100), Member: []byte(fmt.Sprintf("member:%04d", i))}})
+	}
+	// moving members around keeps the spans of the skiplist right
+	for i := 0; i < 1000; i += 3 {
+		dsStore.ZAdd("zset", []model.SortedSetByte{{Score: float64(1000 + i), Member: []byte(fmt.Sprintf("member:%04d", i))}})
+	}
+	all, _ := dsStore.ZRange("zset", 0, -1)
+	if len(all) != 1000 {
+		t.Fatalf("Expected 1000 members, got %d", len(all))
+	}
+	for rank, member := range all {
+		actRank, score, _ := dsStore.ZRank("zset", member.Member, false)
+		if actRank != rank || score != member.Score {
+			t.Fatalf("Expected rank of %s to be %d with score %f, got %d with %f", member.Member, rank, member.Score, actRank, score)
+		}
+		revRank, _, _ := dsStore.ZRank("zset", member.Member, true)
+		if revRank != 999-rank {
+			t.Fatalf("Expected reverse rank of %s to be %d, got %d", member.Member, 999-rank, revRank)
+		}
+		if rank > 0 && (all[rank-1].Score > member.Score || all[rank-1].Score == member.Score && all[rank-1].Member > member.Member) {
+			t.Fatalf("Expected %v to sort before %v", all[rank-1], member)
+		}
+	}
+	page, _ := dsStore.ZRange("zset", 900, 909)
+	if len(page) != 10 || page[0] != all[900] || page[9] != all[909] {
+		t.Errorf("Expected page to be %v, got %v", all[900:910], page)
+	}
+	if rank, _, _ := dsStore.ZRank("zset", "missing", false); rank != -1 {
+		t.Errorf("Expected rank of a missing member to be -1, got %d", rank)
+	}
+}
+
+func TestZRangeBy(t *testing.T) {
+	dsStore := datastore.New()
+	dsStore.ZAdd("zset", []model.SortedSetByte{
+		{Score: 1, Member: []byte("a")}, {Score: 2, Member: []byte("b")}, {Score: 2.5, Member: []byte("c")},
+		{Score: 3, Member: []byte("d")}, {Score: 4, Member: []byte("e")},
+	})
+	dsStore.ZAdd("lex", []model.SortedSetByte{
+		{Member: []byte("a")}, {Member: []byte("b")}, {Member: []byte("c")}, {Member: []byte("d")},
+	})
+	data, err := dsStore.ZRangeBy("zset", model.ZRangeSpec{Start: -2, Stop: -1})
+	if err != nil {
+		t.Errorf("Expected err to be nil, got %v", err)
+	}
+	if !slices.Equal(data, []model.SortedSet{{Score: 3, Member: "d"}, {Score: 4, Member: "e"}}) {
+		t.Errorf("Expected the last two members to be d e, got %v", data)
+	}
+	data, _ = dsStore.ZRangeBy("zset", model.ZRangeSpec{Start: 0, Stop: 1, Rev: true})
+	if !slices.Equal(data, []model.SortedSet{{Score: 4, Member: "e"}, {Score: 3, Member: "d"}}) {
+		t.Errorf("Expected the reversed range to be e d, got %v", data)
+	}
+	if data, _ = dsStore.ZRangeBy("zset", model.ZRangeSpec{Start: 3, Stop: 1}); len(data) != 0 {
+		t.Errorf("Expected a start after the stop to be empty, got %v", data)
+	}
+	data, _ = dsStore.ZRangeBy("zset", model.ZRangeSpec{By: model.ZRangeByScore, Score: model.ScoreRange{Min: 2, Max: 3}, Count: -1})
+	if !slices.Equal(data, []model.SortedSet{{Score: 2, Member: "b"}, {Score: 2.5, Member: "c"}, {Score: 3, Member: "d"}}) {
+		t.Errorf("Expected scores 2 to 3 to be b c d, got %v", data)
+	}
+	data, _ = dsStore.ZRangeBy("zset", model.ZRangeSpec{By: model.ZRangeByScore, Score: model.ScoreRange{Min: 2, Max: 3, MinExclusive: true, MaxExclusive: true}, Count: -1})
+	if !slices.Equal(data, []model.SortedSet{{Score: 2.5, Member: "c"}}) {
+		t.Errorf("Expected scores (2 to (3 to be c, got %v", data)
+	}
+	data, _ = dsStore.ZRangeBy("zset", model.ZRangeSpec{By: model.ZRangeByScore, Score: model.ScoreRange{Min: math.Inf(-1), Max: math.Inf(1)}, Offset: 1, Count: 2})
+	if !slices.Equal(data, []model.SortedSet{{Score: 2, Member: "b"}, {Score: 2.5, Member: "c"}}) {
+		t.Errorf("Expected LIMIT 1 2 to be b c, got %v", data)
+	}
+	data, _ = dsStore.ZRangeBy("zset", model.ZRangeSpec{By: model.ZRangeByScore, Score: model.ScoreRange{Min: 1, Max: 3}, Rev: true, Offset: 1, Count: -1})
+	if !slices.Equal(data, []model.SortedSet{{Score: 2.5, Member: "c"}, {Score: 2, Member: "b"}, {Score: 1, Member: "a"}}) {
+		t.Errorf("Expected the reversed scores after the first to be c b a, got %v", data)
+	}
+	if data, _ = dsStore.ZRangeBy("zset", model.ZRangeSpec{By: model.ZRangeByScore, Score: model.ScoreRange{Min: 5, Max: 6}, Count: -1}); len(data) != 0 {
+		t.Errorf("Expected scores above the set to be empty, got %v", data)
+	}
+	data, _ = dsStore.ZRangeBy("lex", model.ZRangeSpec{By: model.ZRangeByLex, Lex: model.LexRange{Min: "b", MaxInfinite: true}, Count: -1})
+	if !slices.Equal(data, []model.SortedSet{{Member: "b"}, {Member: "c"}, {Member: "d"}}) {
+		t.Errorf("Expected [b to + to be b c d, got %v", data)
+	}
+	data, _ = dsStore.ZRangeBy("lex", model.ZRangeSpec{By: model.ZRangeByLex, Lex: model.LexRange{MinInfinite: true, Max: "c", MaxExclusive: true}, Rev: true, Count: -1})
+	if !slices.Equal(data, []model.SortedSet{{Member: "b"}, {Member: "a"}}) {
+		t.Errorf("Expected - to (c reversed to be b a, got %v", data)
+	}
+	if data, _ = dsStore.ZRangeBy("missing", model.ZRangeSpec{Start: 0, Stop: -1}); len(data) != 0 {
+		t.Errorf("Expected a missing key to be empty, got %v", data)
+	}
+
+	stored, _ := dsStore.ZRangeStore("dst", "zset", model.ZRangeSpec{By: model.ZRangeByScore, Score: model.ScoreRange{Min: 3, Max: math.Inf(1)}, Count: -1})
+	data, _ = dsStore.ZRange("dst", 0, -1)
+	if stored != 2 || !slices.Equal(data, []model.SortedSet{{Score: 3, Member: "d"}, {Score: 4, Member: "e"}}) {
+		t.Errorf("Expected dst to hold d e, got %d %v", stored, data)
+	}
+	stored, _ = dsStore.ZRangeStore("dst", "missing", model.ZRangeSpec{Start: 0, Stop: -1})
+	if value, _ := dsStore.Keys("dst"); stored != 0 || len(value) != 0 {
+		t.Errorf("Expected an empty range to delete dst, got %d %v", stored, value)
+	}
+}
+
+func TestZSetPointOperations(t *testing.T) {
+	dsStore := datastore.New()
+	dsStore.ZAdd("zset", []model.SortedSetByte{
+		{Score: 1, Member: []byte("a")}, {Score: 2, Member: []byte("b")}, {Score: 3, Member: []byte("c")},
+		{Score: 4, Member: []byte("d")}, {Score: 5, Member: []byte("e")},
+	})
+	scores, _ := dsStore.ZMScore("zset", []string{"b", "missing"})
+	if scores[0] == nil || *scores[0] != 2 || scores[1] != nil {
+		t.Errorf("Expected scores to be [2 nil], got %v", scores)
+	}
+	count, _ := dsStore.ZCount("zset", model.ZRangeSpec{By: model.ZRangeByScore, Score: model.ScoreRange{Min: 2, Max: 4, MaxExclusive: true}})
+	if count != 2 {
+		t.Errorf("Expected count to be 2, got %d", count)
+	}
+	score, _ := dsStore.ZIncrBy("zset", 10, "a")
+	if rank, _, _ := dsStore.ZRank("zset", "a", false); score != 11 || rank != 4 {
+		t.Errorf("Expected a to move to rank 4 with score 11, got %d with %f", rank, score)
+	}
+	dsStore.ZIncrBy("inf", math.Inf(1), "a")
+	if _, err := dsStore.ZIncrBy("inf", math.Inf(-1), "a"); err != errs.ScoreNaN {
+		t.Errorf("Expected err to be %v, got %v", errs.ScoreNaN, err)
+	}
+	removed, _ := dsStore.ZRem("zset", []string{"b", "missing"})
+	if card, _ := dsStore.ZCard("zset"); removed != 1 || card != 4 {
+		t.Errorf("Expected 1 member removed and 4 left, got %d and %d", removed, card)
+	}
+	removed, _ = dsStore.ZRemRangeBy("zset", model.ZRangeSpec{By: model.ZRangeByScore, Score: model.ScoreRange{Min: 3, Max: 4}, Count: -1})
+	if removed != 2 {
+		t.Errorf("Expected 2 members removed, got %d", removed)
+	}
+	removed, _ = dsStore.ZRemRangeBy("zset", model.ZRangeSpec{Start: 0, Stop: -1})
+	if keys, _ := dsStore.Keys("zset"); removed != 2 || len(keys) != 0 {
+		t.Errorf("Expected the last members removed to delete the key, got %d removed and keys %v", removed, keys)
+	}
+}
+
+func TestZAddWithOptions(t *testing.T) {
+	dsStore := datastore.New()
+	dsStore.ZAdd("board", []model.SortedSetByte{{Score: 10, Member: []byte("a")}, {Score: 20, Member: []byte("b")}})
+	// GT only raises scores but still adds new members
+	count, _, err := dsStore.ZAddWithOptions("board", []model.SortedSetByte{{Score: 15, Member: []byte("a")}, {Score: 5, Member: []byte("b")}, {Score: 1, Member: []byte("c")}}, model.ZAddOptions{GT: true, CH: true})
+	if err != nil {
+		t.Errorf("Expected err to be nil, got %v", err)
+	}
+	data, _ := dsStore.ZRange("board", 0, -1)
+	if count != 2 || !slices.Equal(data, []model.SortedSet{{Score: 1, Member: "c"}, {Score: 15, Member: "a"}, {Score: 20, Member: "b"}}) {
+		t.Errorf("Expected GT CH to change 2 and leave c:1 a:15 b:20, got %d and %v", count, data)
+	}
+	count, _, _ = dsStore.ZAddWithOptions("board", []model.SortedSetByte{{Score: 30, Member: []byte("a")}, {Score: 0, Member: []byte("b")}}, model.ZAddOptions{LT: true})
+	data, _ = dsStore.ZRange("board", 0, -1)
+	if count != 0 || !slices.Equal(data, []model.SortedSet{{Score: 0, Member: "b"}, {Score: 1, Member: "c"}, {Score: 15, Member: "a"}}) {
+		t.Errorf("Expected LT to add 0 and leave b:0 c:1 a:15, got %d and %v", count, data)
+	}
+	count, _, _ = dsStore.ZAddWithOptions("board", []model.SortedSetByte{{Score: 99, Member: []byte("a")}, {Score: 2, Member: []byte("d")}}, model.ZAddOptions{NX: true})
+	data, _ = dsStore.ZRange("board", 0, -1)
+	if count != 1 || !slices.Equal(data, []model.SortedSet{{Score: 0, Member: "b"}, {Score: 1, Member: "c"}, {Score: 2, Member: "d"}, {Score: 15, Member: "a"}}) {
+		t.Errorf("Expected NX to add 1 and leave b:0 c:1 d:2 a:15, got %d and %v", count, data)
+	}
+	count, _, _ = dsStore.ZAddWithOptions("board", []model.SortedSetByte{{Score: 3, Member: []byte("d")}, {Score: 4, Member: []byte("e")}}, model.ZAddOptions{XX: true, CH: true})
+	data, _ = dsStore.ZRange("board", 0, -1)
+	if count != 1 || !slices.Equal(data, []model.SortedSet{{Score: 0, Member: "b"}, {Score: 1, Member: "c"}, {Score: 3, Member: "d"}, {Score: 15, Member: "a"}}) {
+		t.Errorf("Expected XX CH to change 1 and leave b:0 c:1 d:3 a:15, got %d and %v", count, data)
+	}
+	_, score, _ := dsStore.ZAddWithOptions("board", []model.SortedSetByte{{Score: 5, Member: []byte("a")}}, model.ZAddOptions{Incr: true})
+	if score == nil || *score != 20 {
+		t.Errorf("Expected INCR to return 20, got %v", score)
+	}
+	_, score, _ = dsStore.ZAddWithOptions("board", []model.SortedSetByte{{Score: -5, Member: []byte("a")}}, model.ZAddOptions{Incr: true, GT: true})
+	if score != nil {
+		t.Errorf("Expected INCR GT lowering the score to return nil, got %v", *score)
+	}
+	dsStore.ZAddWithOptions("new", []model.SortedSetByte{{Score: 1, Member: []byte("a")}}, model.ZAddOptions{XX: true})
+	if keys, _ := dsStore.Keys("new"); len(keys) != 0 {
+		t.Errorf("Expected ZADD XX not to create the key, got %v", keys)
+	}
+}
+
+func TestZSetOperations(t *testing.T) {
+	dsStore := datastore.New()
+	dsStore.ZAdd("day1", []model.SortedSetByte{{Score: 1, Member: []byte("a")}, {Score: 2, Member: []byte("b")}, {Score: 3, Member: []byte("c")}})
+	dsStore.ZAdd("day2", []model.SortedSetByte{{Score: 10, Member: []byte("b")}, {Score: 20, Member: []byte("c")}, {Score: 30, Member: []byte("d")}})
+	data, err := dsStore.ZSetOperation(model.ZSetUnion, []string{"day1", "day2", "missing"}, model.ZSetOpOptions{})
+	if err != nil {
+		t.Errorf("Expected err to be nil, got %v", err)
+	}
+	if !slices.Equal(data, []model.SortedSet{{Score: 1, Member: "a"}, {Score: 12, Member: "b"}, {Score: 23, Member: "c"}, {Score: 30, Member: "d"}}) {
+		t.Errorf("Expected the union to be a:1 b:12 c:23 d:30, got %v", data)
+	}
+	data, _ = dsStore.ZSetOperation(model.ZSetUnion, []string{"day1", "day2"}, model.ZSetOpOptions{Weights: []float64{10, 1}, Aggregate: model.AggregateMax})
+	if !slices.Equal(data, []model.SortedSet{{Score: 10, Member: "a"}, {Score: 20, Member: "b"}, {Score: 30, Member: "c"}, {Score: 30, Member: "d"}}) {
+		t.Errorf("Expected the weighted MAX union to be a:10 b:20 c:30 d:30, got %v", data)
+	}
+	data, _ = dsStore.ZSetOperation(model.ZSetInter, []string{"day1", "day2"}, model.ZSetOpOptions{Aggregate: model.AggregateMin})
+	if !slices.Equal(data, []model.SortedSet{{Score: 2, Member: "b"}, {Score: 3, Member: "c"}}) {
+		t.Errorf("Expected the MIN intersection to be b:2 c:3, got %v", data)
+	}
+	if data, _ = dsStore.ZSetOperation(model.ZSetInter, []string{"day1", "missing"}, model.ZSetOpOptions{}); len(data) != 0 {
+		t.Errorf("Expected the intersection with a missing key to be empty, got %v", data)
+	}
+	data, _ = dsStore.ZSetOperation(model.ZSetDiff, []string{"day1", "day2"}, model.ZSetOpOptions{})
+	if !slices.Equal(data, []model.SortedSet{{Score: 1, Member: "a"}}) {
+		t.Errorf("Expected the difference to be a:1, got %v", data)
+	}
+	data, _ = dsStore.ZSetOperation(model.ZSetDiff, []string{"day2", "missing"}, model.ZSetOpOptions{})
+	if !slices.Equal(data, []model.SortedSet{{Score: 10, Member: "b"}, {Score: 20, Member: "c"}, {Score: 30, Member: "d"}}) {
+		t.Errorf("Expected the difference with a missing key to be day2, got %v", data)
+	}
+	stored, _ := dsStore.ZSetOperationStore("day1", model.ZSetInter, []string{"day1", "day2"}, model.ZSetOpOptions{})
+	data, _ = dsStore.ZRange("day1", 0, -1)
+	if stored != 2 || !slices.Equal(data, []model.SortedSet{{Score: 12, Member: "b"}, {Score: 23, Member: "c"}}) {
+		t.Errorf("Expected day1 to hold b:12 c:23, got %d %v", stored, data)
+	}
+	if card, _ := dsStore.ZInterCard([]string{"day1", "day2"}, 1); card != 1 {
+		t.Errorf("Expected the limited card to be 1, got %d", card)
+	}
+	dsStore.Set("string", []byte("test123"))
+	if _, err := dsStore.ZSetOperation(model.ZSetUnion, []string{"day2", "string"}, model.ZSetOpOptions{}); err != errs.WrongType {
+		t.Errorf("Expected err to be %v, got %v", errs.WrongType, err)
+	}
+}
+
+func TestZInterCard(t *testing.T) {
+	dsStore := datastore.New()
+	var big, small []model.SortedSetByte
+	for i := 0; i < 10000; i++ {
+		big = append(big, model.SortedSetByte{Score: float64(i), Member: []byte(fmt.Sprint(i))})
+		if i%2 == 0 {
+			small = append(small, model.SortedSetByte{Score: float64(i), Member: []byte(fmt.Sprint(i))})
+		}
+	}
+	dsStore.ZAdd("big", big)
+	dsStore.ZAdd("small", small)
+	if card, _ := dsStore.ZInterCard([]string{"big", "small"}, 0); card != 5000 {
+		t.Errorf("Expected card to be 5000, got %d", card)
+	}
+	if card, _ := dsStore.ZInterCard([]string{"big", "small"}, 3); card != 3 {
+		t.Errorf("Expected the limited card to be 3, got %d", card)
+	}
+	if card, _ := dsStore.ZInterCard([]string{"big", "missing"}, 0); card != 0 {
+		t.Errorf("Expected card to be 0, got %d", card)
+	}
+	// counting doesn't build the intersection
+	allocs := testing.AllocsPerRun(10, func() { dsStore.ZInterCard([]string{"big", "small"}, 3) })
+	if allocs > 20 {
+		t.Errorf("Expected ZInterCard not to allocate the intersection, got %v allocations", allocs)
+	}
+}
+
+func TestBZPop(t *testing.T) {
+	dsStore := datastore.New()
+	// clients blocked on the same key are served oldest first
+	results := make(chan string, 2)
+	for _, name := range []string{"first", "second"} {
+		go func(name string) {
+			key, data, _ := dsStore.BZPop([]string{"other", "queue"}, false, 1, 0, nil)
+			results <- fmt.Sprintf("%s:%s:%s", name, key, data[0].Member)
+		}(name)
+		time.Sleep(50 * time.Millisecond)
+	}
+	dsStore.ZAdd("queue", []model.SortedSetByte{{Score: 2, Member: []byte("b")}, {Score: 1, Member: []byte("a")}})
+	served := []string{<-results, <-results}
+	if !utils.Contains(served, "first:queue:a") || !utils.Contains(served, "second:queue:b") {
+		t.Errorf("Expected first:queue:a and second:queue:b, got %v", served)
+	}
+	if keys, _ := dsStore.Keys("queue"); len(keys) != 0 {
+		t.Errorf("Expected the emptied queue to be deleted, got %v", keys)
+	}
+
+	start := time.Now()
+	key, _, _ := dsStore.BZPop([]string{"queue"}, false, 1, 100*time.Millisecond, nil)
+	if key != "" || time.Since(start) < 100*time.Millisecond {
+		t.Errorf("Expected the pop to time out after 100ms, got key %q after %v", key, time.Since(start))
+	}
+	done := make(chan struct{})
+	close(done)
+	key, _, _ = dsStore.BZPop([]string{"queue"}, false, 1, 0, done)
+	// a client that gave up doesn't take members anymore
+	dsStore.ZAdd("queue", []model.SortedSetByte{{Score: 1, Member: []byte("a")}})
+	if card, _ := dsStore.ZCard("queue"); key != "" || card != 1 {
+		t.Errorf("Expected the cancelled pop to leave the member, got key %q and card %d", key, card)
+	}
+	key, data, _ := dsStore.BZPop([]string{"queue"}, true, 5, 0, nil)
+	if key != "queue" || len(data) != 1 {
+		t.Errorf("Expected to pop a from queue right away, got %q %v", key, data)
+	}
+}
+
+func TestZPopAndRandMember(t *testing.T) {
+	dsStore := datastore.New()
+	dsStore.ZAdd("zset", []model.SortedSetByte{{Score: 1, Member: []byte("a")}, {Score: 2, Member: []byte("b")}, {Score: 3, Member: []byte("c")}})
+	members, _ := dsStore.ZRandMember("zset", 2)
+	if len(members) != 2 || members[0].Member == members[1].Member {
+		t.Errorf("Expected 2 different members, got %v", members)
+	}
+	if members, _ = dsStore.ZRandMember("zset", -5); len(members) != 5 {
+		t.Errorf("Expected 5 members, got %v", members)
+	}
+	if members, _ = dsStore.ZRandMember("zset", 10); len(members) != 3 {
+		t.Errorf("Expected the whole set, got %v", members)
+	}
+	key, data, _ := dsStore.ZPop([]string{"missing", "zset"}, true, 2)
+	if key != "zset" || len(data) != 2 || data[0].Member != "c" || data[1].Member != "b" {
+		t.Errorf("Expected to pop c and b from zset, got %q %v", key, data)
+	}
+}
+
+func TestListPushPopAcrossChunks(t *testing.T) {
+	dsStore := datastore.New()
+	// the expected list, built the slow way
+	var expected []string
+	for i := 0; i < 1000; i++ {
+		element := fmt.Sprint(i)
+		if i%3 == 0 {
+			dsStore.Push("queue", []string{element}, true)
+			expected = append([]string{element}, expected...)
+		} else {
+			dsStore.Push("queue", []string{element}, false)
+			expected = append(expected, element)
+		}
+	}
+	if length, _ := dsStore.LLen("queue"); length != len(expected) {
+		t.Fatalf("Expected length to be %d, got %d", len(expected), length)
+	}
+	elements, _ := dsStore.LRange("queue", 0, -1)
+	if fmt.Sprint(elements) != fmt.Sprint(expected) {
+		t.Errorf("Expected the whole list to be %v, got %v", expected, elements)
+	}
+	elements, _ = dsStore.LRange("queue", -300, 700)
+	if fmt.Sprint(elements) != fmt.Sprint(expected[700:701]) {
+		t.Errorf("Expected range -300 700 to be %v, got %v", expected[700:701], elements)
+	}
+	for _, index := range []int{0, 127, 128, 500, 999, -1, -129} {
+		element, _ := dsStore.LIndex("queue", index)
+		i := index
+		if i < 0 {
+			i += len(expected)
+		}
+		if string(element) != expected[i] {
+			t.Errorf("Expected element %d to be %s, got %s", index, expected[i], element)
+		}
+	}
+	if element, _ := dsStore.LIndex("queue", 1000); element != nil {
+		t.Errorf("Expected an index out of range to be nil, got %s", element)
+	}
+	popped, _ := dsStore.Pop("queue", true, 200)
+	if fmt.Sprint(popped) != fmt.Sprint(expected[:200]) {
+		t.Errorf("Expected LPOP to return %v, got %v", expected[:200], popped)
+	}
+	popped, _ = dsStore.Pop("queue", false, 2)
+	if fmt.Sprint(popped) != fmt.Sprint([]string{expected[999], expected[998]}) {
+		t.Errorf("Expected RPOP to return the last two elements, got %v", popped)
+	}
+	popped, _ = dsStore.Pop("queue", false, 5000)
+	if keys, _ := dsStore.Keys("queue"); len(popped) != 798 || len(keys) != 0 {
+		t.Errorf("Expected the last 798 elements popped to delete the key, got %d and keys %v", len(popped), keys)
+	}
+	if popped, _ := dsStore.Pop("queue", true, 1); popped != nil {
+		t.Errorf("Expected popping a missing key to return nil, got %v", popped)
+	}
+}
+
+func TestListEditing(t *testing.T) {
+	dsStore := datastore.New()
+	dsStore.Push("list", []string{"a", "b", "a", "c", "a", "d"}, false)
+	if err := dsStore.LSet("list", -1, "e"); err != nil {
+		t.Errorf("Expected err to be nil, got %v", err)
+	}
+	if err := dsStore.LSet("list", 6, "e"); err != errs.IndexOutOfRange {
+		t.Errorf("Expected err to be %v, got %v", errs.IndexOutOfRange, err)
+	}
+	if err := dsStore.LSet("missing", 0, "e"); err != errs.NoSuchKey {
+		t.Errorf("Expected err to be %v, got %v", errs.NoSuchKey, err)
+	}
+	removed, _ := dsStore.LRem("list", -2, "a")
+	if elements, _ := dsStore.LRange("list", 0, -1); removed != 2 || fmt.Sprint(elements) != "[a b c e]" {
+		t.Errorf("Expected the last two a removed, got %d and %v", removed, elements)
+	}
+	length, _ := dsStore.LInsert("list", true, "b", "x")
+	dsStore.LInsert("list", false, "a", "y")
+	if elements, _ := dsStore.LRange("list", 0, -1); length != 5 || fmt.Sprint(elements) != "[y a b x c e]" {
+		t.Errorf("Expected the inserts to give [y a b x c e], got %d and %v", length, elements)
+	}
+	if length, _ := dsStore.LInsert("list", true, "missing", "x"); length != -1 {
+		t.Errorf("Expected a missing pivot to return -1, got %d", length)
+	}
+	dsStore.LTrim("list", 1, -2)
+	if elements, _ := dsStore.LRange("list", 0, -1); fmt.Sprint(elements) != "[a b x c]" {
+		t.Errorf("Expected the trimmed list to be [a b x c], got %v", elements)
+	}
+	dsStore.LTrim("list", 5, 10)
+	if keys, _ := dsStore.Keys("list"); len(keys) != 0 {
+		t.Errorf("Expected trimming every element to delete the key, got %v", keys)
+	}
+
+	// a pivot in a full chunk splits it
+	for i := 0; i < 300; i++ {
+		dsStore.Push("big", []string{fmt.Sprint(i)}, false)
+	}
+	dsStore.LInsert("big", true, "100", "x")
+	if element, _ := dsStore.LIndex("big", 101); string(element) != "x" {
+		t.Errorf("Expected x after 100, got %s", element)
+	}
+	if removed, _ := dsStore.LRem("big", 0, "x"); removed != 1 {
+		t.Errorf("Expected x to be removed, got %d", removed)
+	}
+	if elements, _ := dsStore.LRange("big", 99, 101); fmt.Sprint(elements) != "[99 100 101]" {
+		t.Errorf("Expected [99 100 101], got %v", elements)
+	}
+
+	dsStore.Set("string", []byte("value"))
+	if _, err := dsStore.Push("string", []string{"a"}, true); err != errs.WrongType {
+		t.Errorf("Expected err to be %v, got %v", errs.WrongType, err)
+	}
+	if _, err := dsStore.LRange("string", 0, -1); err != errs.WrongType {
+		t.Errorf("Expected err to be %v, got %v", errs.WrongType, err)
+	}
+	if _, err := dsStore.ZCard("big"); err != errs.WrongType {
+		t.Errorf("Expected err to be %v, got %v", errs.WrongType, err)
+	}
+	if _, keys, _ := dsStore.Scan(0, "*", 10, "list"); fmt.Sprint(keys) != "[big]" {
+		t.Errorf("Expected SCAN TYPE list to return [big], got %v", keys)
+	}
+}
+
+func TestBlockingListCommands(t *testing.T) {
+	dsStore := datastore.New()
+	// workers blocked on the same queue get the jobs oldest first
+	results := make(chan string, 2)
+	for _, name := range []string{"first", "second"} {
+		go func(name string) {
+			key, element, _ := dsStore.BPop([]string{"other", "jobs"}, true, 0, nil)
+			results <- fmt.Sprintf("%s:%s:%s", name, key, element)
+		}(name)
+		time.Sleep(50 * time.Millisecond)
+	}
+	dsStore.Push("jobs", []string{"a", "b"}, false)
+	served := []string{<-results, <-results}
+	if !utils.Contains(served, "first:jobs:a") || !utils.Contains(served, "second:jobs:b") {
+		t.Errorf("Expected first:jobs:a and second:jobs:b, got %v", served)
+	}
+	start := time.Now()
+	if key, _, _ := dsStore.BPop([]string{"jobs"}, false, 100*time.Millisecond, nil); key != "" || time.Since(start) < 100*time.Millisecond {
+		t.Errorf("Expected the pop to time out after 100ms, got key %q after %v", key, time.Since(start))
+	}
+
+	// a job moves atomically onto the processing list
+	moved := make(chan []byte)
+	go func() {
+		element, _ := dsStore.BLMove("jobs", "processing", false, true, 0, nil)
+		moved <- element
+	}()
+	time.Sleep(50 * time.Millisecond)
+	dsStore.Push("jobs", []string{"c"}, true)
+	if element := <-moved; string(element) != "c" {
+		t.Errorf("Expected c to be moved, got %s", element)
+	}
+	if elements, _ := dsStore.LRange("processing", 0, -1); fmt.Sprint(elements) != "[c]" {
+		t.Errorf("Expected processing to hold [c], got %v", elements)
+	}
+	if keys, _ := dsStore.Keys("jobs"); len(keys) != 0 {
+		t.Errorf("Expected the emptied jobs list to be deleted, got %v", keys)
+	}
+
+	// a move onto the list it blocks on rotates it without serving itself again
+	go func() {
+		element, _ := dsStore.BLMove("ring", "ring", true, false, 0, nil)
+		moved <- element
+	}()
+	time.Sleep(50 * time.Millisecond)
+	dsStore.Push("ring", []string{"x"}, false)
+	if element := <-moved; string(element) != "x" {
+		t.Errorf("Expected x to be moved, got %s", element)
+	}
+	dsStore.Push("ring", []string{"y", "z"}, false)
+	dsStore.LMove("ring", "ring", true, false)
+	if elements, _ := dsStore.LRange("ring", 0, -1); fmt.Sprint(elements) != "[y z x]" {
+		t.Errorf("Expected the rotated ring to be [y z x], got %v", elements)
+	}
+
+	// a pushed element reaches a BLPOP waiter through a BLMOVE waiter
+	go func() {
+		_, element, _ := dsStore.BPop([]string{"done"}, true, 0, nil)
+		moved <- element
+	}()
+	time.Sleep(50 * time.Millisecond)
+	go func() {
+		element, _ := dsStore.BLMove("pending", "done", true, true, 0, nil)
+		moved <- element
+	}()
+	time.Sleep(50 * time.Millisecond)
+	dsStore.Push("pending", []string{"job"}, false)
+	if first, second := <-moved, <-moved; string(first) != "job" || string(second) != "job" {
+		t.Errorf("Expected job to be moved then popped, got %s and %s", first, second)
+	}
+
+	if element, _ := dsStore.LMove("missing", "processing", true, true); element != nil {
+		t.Errorf("Expected moving from a missing list to return nil, got %s", element)
+	}
+	dsStore.Set("string", []byte("value"))
+	if _, err := dsStore.LMove("ring", "string", true, true); err != errs.WrongType {
+		t.Errorf("Expected err to be %v, got %v", errs.WrongType, err)
+	}
+	if length, _ := dsStore.LLen("ring"); length != 3 {
+		t.Errorf("Expected a failed move to leave the source alone, got length %d", length)
+	}
+	if _, _, err := dsStore.BPop([]string{"string"}, true, 0, nil); err != errs.WrongType {
+		t.Errorf("Expected err to be %v, got %v", errs.WrongType, err)
+	}
+}
+
+func TestHash(t *testing.T) {
+	dsStore := datastore.New()
+	added, _ := dsStore.HSet("user", []model.FieldValue{{Field: "name", Value: "saurabh"}, {Field: "visits", Value: "1"}})
+	if added != 2 {
+		t.Errorf("Expected 2 fields added, got %d", added)
+	}
+	// updating a field leaves the others alone
+	added, _ = dsStore.HSet("user", []model.FieldValue{{Field: "name", Value: "care"}, {Field: "city", Value: "pune"}})
+	if name, _ := dsStore.HGet("user", "name"); added != 1 || string(name) != "care" {
+		t.Errorf("Expected 1 field added and name care, got %d and %s", added, name)
+	}
+	values, _ := dsStore.HMGet("user", []string{"city", "missing", "visits"})
+	if fmt.Sprintf("%s", values) != "[pune  1]" {
+		t.Errorf("Expected values [pune  1], got %s", values)
+	}
+	if set, _ := dsStore.HSetNX("user", "name", "other"); set {
+		t.Errorf("Expected HSETNX not to replace name")
+	}
+	if length, _ := dsStore.HStrLen("user", "city"); length != 4 {
+		t.Errorf("Expected the length of city to be 4, got %d", length)
+	}
+	visits, _ := dsStore.HIncrBy("user", "visits", 41)
+	if visits != 42 {
+		t.Errorf("Expected visits to be 42, got %d", visits)
+	}
+	if _, err := dsStore.HIncrBy("user", "name", 1); err != errs.HashValueNotInt {
+		t.Errorf("Expected err to be %v, got %v", errs.HashValueNotInt, err)
+	}
+	if _, err := dsStore.HIncrBy("user", "visits", math.MaxInt64); err != errs.IncrOverflow {
+		t.Errorf("Expected err to be %v, got %v", errs.IncrOverflow, err)
+	}
+	balance, _ := dsStore.HIncrByFloat("user", "balance", 10.5)
+	balance, _ = dsStore.HIncrByFloat("user", "balance", 0.1)
+	if value, _ := dsStore.HGet("user", "balance"); balance != 10.6 || string(value) != "10.6" {
+		t.Errorf("Expected balance to be 10.6, got %v stored as %s", balance, value)
+	}
+	if _, err := dsStore.HIncrByFloat("user", "name", 1); err != errs.HashValueNotFloat {
+		t.Errorf("Expected err to be %v, got %v", errs.HashValueNotFloat, err)
+	}
+	if _, err := dsStore.HIncrByFloat("user", "balance", math.MaxFloat64); err != nil {
+		t.Errorf("Expected err to be nil, got %v", err)
+	}
+	if _, err := dsStore.HIncrByFloat("user", "balance", math.MaxFloat64); err != errs.IncrNaNOrInfinity {
+		t.Errorf("Expected err to be %v, got %v", errs.IncrNaNOrInfinity, err)
+	}
+	fields, _ := dsStore.HKeys("user", false)
+	all, _ := dsStore.HGetAll("user")
+	if length, _ := dsStore.HLen("user"); length != 4 || len(fields) != 4 || len(all) != 4 || !utils.Contains(fields, "balance") {
+		t.Errorf("Expected 4 fields, got %d, %v and %v", length, fields, all)
+	}
+	if exists, _ := dsStore.HExists("user", "city"); !exists {
+		t.Errorf("Expected city to exist")
+	}
+
+	random, _ := dsStore.HRandField("user", 3)
+	if len(random) != 3 || random[0].Field == random[1].Field || random[1].Field == random[2].Field || random[0].Field == random[2].Field {
+		t.Errorf("Expected 3 different fields, got %v", random)
+	}
+	if random, _ := dsStore.HRandField("user", -10); len(random) != 10 {
+		t.Errorf("Expected 10 fields with repeats, got %v", random)
+	}
+	if random, _ := dsStore.HRandField("user", 10); len(random) != 4 {
+		t.Errorf("Expected the whole hash, got %v", random)
+	}
+
+	seen := map[string]bool{}
+	var cursor uint64
+	for {
+		var batch []model.FieldValue
+		cursor, batch, _ = dsStore.HScan("user", cursor, "*i*", 1)
+		for _, field := range batch {
+			seen[field.Field] = true
+		}
+		if cursor == 0 {
+			break
+		}
+	}
+	if len(seen) != 2 || !seen["city"] || !seen["visits"] {
+		t.Errorf("Expected HSCAN to return city and visits, got %v", seen)
+	}
+
+	removed, _ := dsStore.HDel("user", []string{"name", "city", "visits", "balance", "missing"})
+	if keys, _ := dsStore.Keys("user"); removed != 4 || len(keys) != 0 {
+		t.Errorf("Expected the last fields removed to delete the key, got %d removed and keys %v", removed, keys)
+	}
+
+	dsStore.Set("string", []byte("value"))
+	dsStore.ZAdd("zset", []model.SortedSetByte{{Score: 1, Member: []byte("a")}})
+	for _, key := range []string{"string", "zset"} {
+		if _, err := dsStore.HSet(key, []model.FieldValue{{Field: "a", Value: "b"}}); err != errs.WrongType {
+			t.Errorf("Expected HSET on %s to fail with %v, got %v", key, errs.WrongType, err)
+		}
+		if _, err := dsStore.HGet(key, "a"); err != errs.WrongType {
+			t.Errorf("Expected HGET on %s to fail with %v, got %v", key, errs.WrongType, err)
+		}
+	}
+	dsStore.HSet("user", []model.FieldValue{{Field: "a", Value: "b"}})
+	if _, err := dsStore.Get("user"); err != errs.WrongType {
+		t.Errorf("Expected GET on a hash to fail with %v, got %v", errs.WrongType, err)
+	}
+}
+
+func TestHashRandFieldLarge(t *testing.T) {
+	dsStore := datastore.New()
+	var fields []model.FieldValue
+	for i := 0; i < 10000; i++ {
+		fields = append(fields, model.FieldValue{Field: fmt.Sprint("field", i), Value: fmt.Sprint(i)})
+	}
+	dsStore.HSet("big", fields)
+	random, _ := dsStore.HRandField("big", 5)
+	seen := map[string]bool{}
+	for _, fv := range random {
+		if fv.Field != "field"+fv.Value || seen[fv.Field] {
+			t.Errorf("Expected 5 different fields with their values, got %v", random)
+		}
+		seen[fv.Field] = true
+	}
+	if len(random) != 5 {
+		t.Errorf("Expected 5 fields, got %d", len(random))
+	}
+	if random, _ := dsStore.HRandField("big", 6000); len(random) != 6000 {
+		t.Errorf("Expected 6000 fields, got %d", len(random))
+	}
+	// a few fields are drawn without copying the hash
+	allocs := testing.AllocsPerRun(10, func() { dsStore.HRandField("big", 5) })
+	if allocs > 50 {
+		t.Errorf("Expected HRandField not to copy the hash, got %v allocations", allocs)
+	}
+}
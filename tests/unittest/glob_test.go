@@ -0,0 +1,45 @@
+package unittest
+
+import (
+	"testing"
+
+	"github.com/saurabhy27/redis-database/utils"
+)
+
+func TestGlobMatch(t *testing.T) {
+	cases := []struct {
+		pattern string
+		str     string
+		exp     bool
+	}{
+		{"*", "", true},
+		{"*", "user:1", true},
+		{"user:*", "user:1", true},
+		{"user:*", "xuser:1", false},
+		{"user", "user:1", false},
+		{"h?llo", "hello", true},
+		{"h?llo", "hllo", false},
+		{"h*llo", "heeeello", true},
+		{"h*llo", "hello world", false},
+		{"h[ae]llo", "hallo", true},
+		{"h[ae]llo", "hillo", false},
+		{"h[^e]llo", "hallo", true},
+		{"h[^e]llo", "hello", false},
+		{"h[a-b]llo", "hbllo", true},
+		{"h[b-a]llo", "hallo", true},
+		{"h[a-b]llo", "hcllo", false},
+		{"h\\*llo", "h*llo", true},
+		{"h\\*llo", "hello", false},
+		{"h[\\]]llo", "h]llo", true},
+		{"*a*b*c", "xaybzc", true},
+		{"*a*b*c", "xaybzcd", false},
+		{"a[bc", "ab", true},
+		{"a**", "a", true},
+		{"a\\", "a\\", true},
+	}
+	for _, c := range cases {
+		if act := utils.GlobMatch(c.pattern, c.str); act != c.exp {
+			t.Errorf("Expected %q matching %q to be %v, got %v", c.pattern, c.str, c.exp, act)
+		}
+	}
+}
@@ -1,18 +1,29 @@
 package unittest
 
 import (
+	"slices"
+	"strconv"
 	"testing"
+	"time"
 
+	"github.com/saurabhy27/redis-database/constants"
+	"github.com/saurabhy27/redis-database/datastore"
+	"github.com/saurabhy27/redis-database/errs"
 	"github.com/saurabhy27/redis-database/model"
 	"github.com/saurabhy27/redis-database/processor"
 	req "github.com/saurabhy27/redis-database/request"
 	"github.com/saurabhy27/redis-database/tests/mock"
 )
 
+func command(name string) model.Command {
+	command, _ := req.Lookup(name)
+	return command
+}
+
 func TestProcessGet(t *testing.T) {
 	dataStore := &mock.MockDataStore{}
 	reqProcessor := processor.RequestProcessor{DataStore: dataStore}
-	request := model.Request{Command: req.CMDGet, Params: []string{"test"}}
+	request := model.Request{Command: command(constants.GET), Params: []string{"test"}}
 	response, err := reqProcessor.Process(request)
 	if err != nil {
 		t.Errorf("Expected err to be nil, got %v", err)
@@ -29,7 +40,7 @@ func TestProcessGet(t *testing.T) {
 func TestProcessSet(t *testing.T) {
 	dataStore := &mock.MockDataStore{}
 	reqProcessor := processor.RequestProcessor{DataStore: dataStore}
-	request := model.Request{Command: req.CMDSet, Params: []string{"test", "test123"}}
+	request := model.Request{Command: command(constants.SET), Params: []string{"test", "test123"}}
 	_, err := reqProcessor.Process(request)
 	if err != nil {
 		t.Errorf("Expected err to be nil, got %v", err)
@@ -42,7 +53,7 @@ func TestProcessSet(t *testing.T) {
 func TestProcessDel(t *testing.T) {
 	dataStore := &mock.MockDataStore{}
 	reqProcessor := processor.RequestProcessor{DataStore: dataStore}
-	request := model.Request{Command: req.CMDDel, Params: []string{"test"}}
+	request := model.Request{Command: command(constants.DEL), Params: []string{"test"}}
 	response, err := reqProcessor.Process(request)
 	if err != nil {
 		t.Errorf("Expected err to be nil, got %v", err)
@@ -59,7 +70,7 @@ func TestProcessDel(t *testing.T) {
 func TestProcessKeys(t *testing.T) {
 	dataStore := &mock.MockDataStore{}
 	reqProcessor := processor.RequestProcessor{DataStore: dataStore}
-	request := model.Request{Command: req.CMDKeys, Params: []string{"*"}}
+	request := model.Request{Command: command(constants.KEYS), Params: []string{"*"}}
 	response, err := reqProcessor.Process(request)
 	if err != nil {
 		t.Errorf("Expected err to be nil, got %v", err)
@@ -76,7 +87,7 @@ func TestProcessKeys(t *testing.T) {
 func TestProcessExpire(t *testing.T) {
 	dataStore := &mock.MockDataStore{}
 	reqProcessor := processor.RequestProcessor{DataStore: dataStore}
-	request := model.Request{Command: req.CMDExpire, Params: []string{"test", "1"}}
+	request := model.Request{Command: command(constants.EXPIRE), Params: []string{"test", "1"}}
 	response, err := reqProcessor.Process(request)
 	if err != nil {
 		t.Errorf("Expected err to be nil, got %v", err)
@@ -93,7 +104,7 @@ func TestProcessExpire(t *testing.T) {
 func TestProcessTtl(t *testing.T) {
 	dataStore := &mock.MockDataStore{}
 	reqProcessor := processor.RequestProcessor{DataStore: dataStore}
-	request := model.Request{Command: req.CMDTtl, Params: []string{"test"}}
+	request := model.Request{Command: command(constants.TTL), Params: []string{"test"}}
 	response, err := reqProcessor.Process(request)
 	if err != nil {
 		t.Errorf("Expected err to be nil, got %v", err)
@@ -110,7 +121,7 @@ func TestProcessTtl(t *testing.T) {
 func TestProcessZAdd(t *testing.T) {
 	dataStore := &mock.MockDataStore{}
 	reqProcessor := processor.RequestProcessor{DataStore: dataStore}
-	request := model.Request{Command: req.CMDZAdd, Params: []string{"test", "1", "test123", "2", "test123"}}
+	request := model.Request{Command: command(constants.ZADD), Params: []string{"test", "1", "test123", "2", "test123"}}
 	response, err := reqProcessor.Process(request)
 	if err != nil {
 		t.Errorf("Expected err to be nil, got %v", err)
@@ -127,7 +138,7 @@ func TestProcessZAdd(t *testing.T) {
 func TestProcessZRange(t *testing.T) {
 	dataStore := &mock.MockDataStore{}
 	reqProcessor := processor.RequestProcessor{DataStore: dataStore}
-	request := model.Request{Command: req.CMDZRange, Params: []string{"test", "1", "2"}}
+	request := model.Request{Command: command(constants.ZRANGE), Params: []string{"test", "1", "2"}}
 	response, err := reqProcessor.Process(request)
 	if err != nil {
 		t.Errorf("Expected err to be nil, got %v", err)
@@ -135,8 +146,1152 @@ func TestProcessZRange(t *testing.T) {
 	if !dataStore.ZRangeMocked {
 		t.Errorf("Mocked ZRANGE Function not called")
 	}
-	zRange, _ := response.Value.([]model.SortedSet)
-	if zRange[0].Member != "test123" {
-		t.Errorf("Expected zrange[1] to be test123, got %s", zRange[0].Member)
+	zRange, _ := response.Value.([]string)
+	if len(zRange) != 1 || zRange[0] != "test123" {
+		t.Errorf("Expected zrange to be [test123], got %v", response.Value)
+	}
+}
+
+func TestProcessUnknownCommand(t *testing.T) {
+	reqProcessor := processor.RequestProcessor{DataStore: &mock.MockDataStore{}}
+	request := model.Request{Command: model.Command{Cmd: "NOSUCHCMD"}}
+	_, err := reqProcessor.Process(request)
+	if err != errs.InvalidCommand {
+		t.Errorf("Expected err to be %v, got %v", errs.InvalidCommand, err)
+	}
+}
+
+func TestProcessCommandInfo(t *testing.T) {
+	reqProcessor := processor.RequestProcessor{DataStore: &mock.MockDataStore{}}
+	request := model.Request{Command: command(constants.COMMAND), Params: []string{"INFO", "get", "nosuchcmd"}}
+	response, err := reqProcessor.Process(request)
+	if err != nil {
+		t.Errorf("Expected err to be nil, got %v", err)
+	}
+	infos, _ := response.Value.([]any)
+	if len(infos) != 2 {
+		t.Fatalf("Expected infos to be 2, got %d", len(infos))
+	}
+	info, _ := infos[0].([]any)
+	if string(info[0].([]byte)) != "get" || info[1] != 2 || info[3] != 1 || info[4] != 1 || info[5] != 1 {
+		t.Errorf("Expected get info to be get 2 1 1 1, got %v", info)
+	}
+	if flags, _ := info[2].(model.Set); !slices.Equal(flags, model.Set{"readonly", "fast"}) {
+		t.Errorf("Expected get flags to be readonly fast, got %v", info[2])
+	}
+	if categories, _ := info[6].(model.Set); !slices.Equal(categories, model.Set{"@string", "@read", "@fast"}) {
+		t.Errorf("Expected get categories to be @string @read @fast, got %v", info[6])
+	}
+	if infos[1] != nil {
+		t.Errorf("Expected unknown command info to be nil, got %v", infos[1])
+	}
+
+	// keys after numkeys can't be found from the key positions
+	request = model.Request{Command: command(constants.COMMAND), Params: []string{"INFO", "zunionstore"}}
+	response, _ = reqProcessor.Process(request)
+	infos, _ = response.Value.([]any)
+	info, _ = infos[0].([]any)
+	if flags, _ := info[2].(model.Set); !slices.Contains(flags, "movablekeys") || info[3] != 1 {
+		t.Errorf("Expected zunionstore to have movable keys after the destination, got %v", info)
+	}
+	if categories, _ := info[6].(model.Set); !slices.Contains(categories, "@sortedset") {
+		t.Errorf("Expected zunionstore categories to have @sortedset, got %v", info[6])
+	}
+}
+
+func TestProcessCommandCount(t *testing.T) {
+	reqProcessor := processor.RequestProcessor{DataStore: &mock.MockDataStore{}}
+	request := model.Request{Command: command(constants.COMMAND), Params: []string{"COUNT"}}
+	response, err := reqProcessor.Process(request)
+	if err != nil {
+		t.Errorf("Expected err to be nil, got %v", err)
+	}
+	if response.Value != len(req.Commands()) {
+		t.Errorf("Expected count to be %d, got %v", len(req.Commands()), response.Value)
+	}
+}
+
+func TestProcessCommandDocs(t *testing.T) {
+	reqProcessor := processor.RequestProcessor{DataStore: &mock.MockDataStore{}}
+	request := model.Request{Command: command(constants.COMMAND), Params: []string{"DOCS", "zadd"}}
+	response, err := reqProcessor.Process(request)
+	if err != nil {
+		t.Errorf("Expected err to be nil, got %v", err)
+	}
+	docs, _ := response.Value.(model.Map)
+	if len(docs) != 1 || docs[0].Key != "zadd" {
+		t.Fatalf("Expected docs for zadd, got %v", docs)
+	}
+}
+
+func TestProcessExpireOptions(t *testing.T) {
+	dataStore := datastore.New()
+	reqProcessor := processor.RequestProcessor{DataStore: dataStore}
+	dataStore.Set("test", []byte("test123"))
+	// XX and GT need an existing TTL
+	request := model.Request{Command: command(constants.EXPIRE), Params: []string{"test", "10", "XX"}}
+	response, err := reqProcessor.Process(request)
+	if err != nil {
+		t.Errorf("Expected err to be nil, got %v", err)
+	}
+	if expires, _ := response.Value.(int); expires != 0 {
+		t.Errorf("Expected expire XX to be 0, got %v", response.Value)
+	}
+	request = model.Request{Command: command(constants.EXPIRE), Params: []string{"test", "10", "nx"}}
+	response, _ = reqProcessor.Process(request)
+	if expires, _ := response.Value.(int); expires != 1 {
+		t.Errorf("Expected expire NX to be 1, got %v", response.Value)
+	}
+	request = model.Request{Command: command(constants.EXPIRE), Params: []string{"test", "5", "XX", "GT"}}
+	response, _ = reqProcessor.Process(request)
+	if expires, _ := response.Value.(int); expires != 0 {
+		t.Errorf("Expected expire GT with a shorter TTL to be 0, got %v", response.Value)
+	}
+	request = model.Request{Command: command(constants.PEXPIRE), Params: []string{"test", "20000", "GT"}}
+	response, _ = reqProcessor.Process(request)
+	if expires, _ := response.Value.(int); expires != 1 {
+		t.Errorf("Expected pexpire GT with a longer TTL to be 1, got %v", response.Value)
+	}
+	if ttl := dataStore.PTtl("test"); ttl <= 10000 || ttl > 20000 {
+		t.Errorf("Expected pttl to be about 20000, got %d", ttl)
+	}
+}
+
+func TestProcessExpireInvalidOptions(t *testing.T) {
+	dataStore := datastore.New()
+	reqProcessor := processor.RequestProcessor{DataStore: dataStore}
+	dataStore.Set("test", []byte("test123"))
+	request := model.Request{Command: command(constants.EXPIRE), Params: []string{"test", "10", "nx", "GT"}}
+	if _, err := reqProcessor.Process(request); err != errs.ExpireNXIncompatible {
+		t.Errorf("Expected err to be %v, got %v", errs.ExpireNXIncompatible, err)
+	}
+	request = model.Request{Command: command(constants.EXPIRE), Params: []string{"test", "10", "GT", "LT"}}
+	if _, err := reqProcessor.Process(request); err != errs.ExpireGTLTIncompatible {
+		t.Errorf("Expected err to be %v, got %v", errs.ExpireGTLTIncompatible, err)
+	}
+	request = model.Request{Command: command(constants.EXPIRE), Params: []string{"test", "10", "FOO"}}
+	if _, err := reqProcessor.Process(request); err != errs.SyntaxError {
+		t.Errorf("Expected err to be %v, got %v", errs.SyntaxError, err)
+	}
+	request = model.Request{Command: command(constants.EXPIRE), Params: []string{"test", "ten"}}
+	if _, err := reqProcessor.Process(request); err != errs.InvalidIntValue {
+		t.Errorf("Expected err to be %v, got %v", errs.InvalidIntValue, err)
+	}
+	request = model.Request{Command: command(constants.EXPIRE), Params: []string{"test", "9223372036854775807"}}
+	if _, err := reqProcessor.Process(request); err != errs.InvalidExpireTime {
+		t.Errorf("Expected err to be %v, got %v", errs.InvalidExpireTime, err)
+	}
+	if ttl := dataStore.PTtl("test"); ttl != -1 {
+		t.Errorf("Expected the key to keep no TTL, got %d", ttl)
+	}
+}
+
+func TestProcessPTtl(t *testing.T) {
+	dataStore := datastore.New()
+	reqProcessor := processor.RequestProcessor{DataStore: dataStore}
+	dataStore.Set("test", []byte("test123"))
+	// 600ms past a whole second, so EXPIRETIME rounds up
+	deadline := time.Now().UnixMilli()/1000*1000 + 5600
+	request := model.Request{Command: command(constants.PEXPIREAT), Params: []string{"test", strconv.FormatInt(deadline, 10)}}
+	if _, err := reqProcessor.Process(request); err != nil {
+		t.Errorf("Expected err to be nil, got %v", err)
+	}
+	request = model.Request{Command: command(constants.PTTL), Params: []string{"test"}}
+	response, err := reqProcessor.Process(request)
+	if err != nil {
+		t.Errorf("Expected err to be nil, got %v", err)
+	}
+	if ttl, _ := response.Value.(int); ttl <= 0 || ttl > 5600 {
+		t.Errorf("Expected pttl to be at most 5600, got %v", response.Value)
+	}
+	request = model.Request{Command: command(constants.PEXPIRETIME), Params: []string{"test"}}
+	response, _ = reqProcessor.Process(request)
+	if expireTime, _ := response.Value.(int); int64(expireTime) != deadline {
+		t.Errorf("Expected pexpiretime to be %d, got %v", deadline, response.Value)
+	}
+	request = model.Request{Command: command(constants.EXPIRETIME), Params: []string{"test"}}
+	response, _ = reqProcessor.Process(request)
+	if expireTime, _ := response.Value.(int); int64(expireTime) != deadline/1000+1 {
+		t.Errorf("Expected expiretime to be %d, got %v", deadline/1000+1, response.Value)
+	}
+	request = model.Request{Command: command(constants.PERSIST), Params: []string{"test"}}
+	response, _ = reqProcessor.Process(request)
+	if removed, _ := response.Value.(int); removed != 1 {
+		t.Errorf("Expected persist to be 1, got %v", response.Value)
+	}
+	request = model.Request{Command: command(constants.PTTL), Params: []string{"test"}}
+	response, _ = reqProcessor.Process(request)
+	if ttl, _ := response.Value.(int); ttl != -1 {
+		t.Errorf("Expected pttl to be -1, got %v", response.Value)
+	}
+	request = model.Request{Command: command(constants.PTTL), Params: []string{"missing"}}
+	response, _ = reqProcessor.Process(request)
+	if ttl, _ := response.Value.(int); ttl != -2 {
+		t.Errorf("Expected pttl of a missing key to be -2, got %v", response.Value)
+	}
+}
+
+func TestProcessSetOptions(t *testing.T) {
+	dataStore := datastore.New()
+	reqProcessor := processor.RequestProcessor{DataStore: dataStore}
+	request := model.Request{Command: command(constants.SET), Params: []string{"lock", "token", "nx", "PX", "30000"}}
+	response, err := reqProcessor.Process(request)
+	if err != nil {
+		t.Errorf("Expected err to be nil, got %v", err)
+	}
+	if response.Value != "OK" {
+		t.Errorf("Expected val to be OK, got %v", response.Value)
+	}
+	if ttl := dataStore.PTtl("lock"); ttl <= 0 || ttl > 30000 {
+		t.Errorf("Expected pttl to be at most 30000, got %d", ttl)
+	}
+	// NX doesn't overwrite, the reply is a null bulk string
+	request = model.Request{Command: command(constants.SET), Params: []string{"lock", "other", "NX"}}
+	response, _ = reqProcessor.Process(request)
+	if val, ok := response.Value.([]byte); !ok || val != nil {
+		t.Errorf("Expected val to be a nil bulk string, got %v", response.Value)
+	}
+	request = model.Request{Command: command(constants.SET), Params: []string{"lock", "new", "GET", "KEEPTTL"}}
+	response, _ = reqProcessor.Process(request)
+	if old, _ := response.Value.([]byte); string(old) != "token" {
+		t.Errorf("Expected val to be token, got %v", response.Value)
+	}
+	if val, _ := dataStore.Get("lock"); string(val) != "new" {
+		t.Errorf("Expected val to be new, got %s", string(val))
+	}
+	if ttl := dataStore.PTtl("lock"); ttl <= 0 {
+		t.Errorf("Expected KEEPTTL to keep the TTL, got %d", ttl)
+	}
+}
+
+func TestProcessSetInvalidOptions(t *testing.T) {
+	dataStore := datastore.New()
+	reqProcessor := processor.RequestProcessor{DataStore: dataStore}
+	request := model.Request{Command: command(constants.SET), Params: []string{"k", "v", "NX", "XX"}}
+	if _, err := reqProcessor.Process(request); err != errs.SyntaxError {
+		t.Errorf("Expected err to be %v, got %v", errs.SyntaxError, err)
+	}
+	request = model.Request{Command: command(constants.SET), Params: []string{"k", "v", "EX", "10", "KEEPTTL"}}
+	if _, err := reqProcessor.Process(request); err != errs.SyntaxError {
+		t.Errorf("Expected err to be %v, got %v", errs.SyntaxError, err)
+	}
+	request = model.Request{Command: command(constants.SET), Params: []string{"k", "v", "EX", "10", "PX", "10"}}
+	if _, err := reqProcessor.Process(request); err != errs.SyntaxError {
+		t.Errorf("Expected err to be %v, got %v", errs.SyntaxError, err)
+	}
+	request = model.Request{Command: command(constants.SET), Params: []string{"k", "v", "EX"}}
+	if _, err := reqProcessor.Process(request); err != errs.SyntaxError {
+		t.Errorf("Expected err to be %v, got %v", errs.SyntaxError, err)
+	}
+	request = model.Request{Command: command(constants.SET), Params: []string{"k", "v", "EX", "0"}}
+	if _, err := reqProcessor.Process(request); err != errs.InvalidExpireTime {
+		t.Errorf("Expected err to be %v, got %v", errs.InvalidExpireTime, err)
+	}
+	request = model.Request{Command: command(constants.SET), Params: []string{"k", "v", "PX", "abc"}}
+	if _, err := reqProcessor.Process(request); err != errs.InvalidIntValue {
+		t.Errorf("Expected err to be %v, got %v", errs.InvalidIntValue, err)
+	}
+	if val, _ := dataStore.Get("k"); val != nil {
+		t.Errorf("Expected no value to be set for invalid options, got %s", string(val))
+	}
+}
+
+func TestProcessGetEx(t *testing.T) {
+	dataStore := datastore.New()
+	reqProcessor := processor.RequestProcessor{DataStore: dataStore}
+	request := model.Request{Command: command(constants.SETEX), Params: []string{"test", "100", "test123"}}
+	if _, err := reqProcessor.Process(request); err != nil {
+		t.Errorf("Expected err to be nil, got %v", err)
+	}
+	request = model.Request{Command: command(constants.GETEX), Params: []string{"test", "persist"}}
+	response, err := reqProcessor.Process(request)
+	if err != nil {
+		t.Errorf("Expected err to be nil, got %v", err)
+	}
+	if val, _ := response.Value.([]byte); string(val) != "test123" {
+		t.Errorf("Expected val to be test123, got %v", response.Value)
+	}
+	if ttl := dataStore.PTtl("test"); ttl != -1 {
+		t.Errorf("Expected PERSIST to remove the TTL, got %d", ttl)
+	}
+	request = model.Request{Command: command(constants.GETEX), Params: []string{"test", "EX"}}
+	if _, err := reqProcessor.Process(request); err != errs.SyntaxError {
+		t.Errorf("Expected err to be %v, got %v", errs.SyntaxError, err)
+	}
+	request = model.Request{Command: command(constants.GETDEL), Params: []string{"test"}}
+	response, _ = reqProcessor.Process(request)
+	if val, _ := response.Value.([]byte); string(val) != "test123" {
+		t.Errorf("Expected val to be test123, got %v", response.Value)
+	}
+	if val, _ := dataStore.Get("test"); val != nil {
+		t.Errorf("Expected GETDEL to delete the key, got %s", string(val))
+	}
+}
+
+func TestProcessScan(t *testing.T) {
+	dataStore := datastore.New()
+	reqProcessor := processor.RequestProcessor{DataStore: dataStore}
+	dataStore.Set("test", []byte("test123"))
+	dataStore.Set("care", []byte("care123"))
+	dataStore.ZAdd("tzset", []model.SortedSetByte{{Score: 1, Member: []byte("a")}})
+	request := model.Request{Command: command(constants.SCAN), Params: []string{"0", "MATCH", "t*", "COUNT", "100", "TYPE", "string"}}
+	response, err := reqProcessor.Process(request)
+	if err != nil {
+		t.Errorf("Expected err to be nil, got %v", err)
+	}
+	reply, _ := response.Value.([]any)
+	if len(reply) != 2 {
+		t.Fatalf("Expected a cursor and a batch, got %v", response.Value)
+	}
+	if cursor, _ := reply[0].([]byte); string(cursor) != "0" {
+		t.Errorf("Expected cursor to be 0, got %v", reply[0])
+	}
+	if keys, _ := reply[1].([]string); len(keys) != 1 || keys[0] != "test" {
+		t.Errorf("Expected keys to be [test], got %v", reply[1])
+	}
+}
+
+func TestProcessScanInvalidOptions(t *testing.T) {
+	reqProcessor := processor.RequestProcessor{DataStore: datastore.New()}
+	request := model.Request{Command: command(constants.SCAN), Params: []string{"abc"}}
+	if _, err := reqProcessor.Process(request); err != errs.InvalidCursor {
+		t.Errorf("Expected err to be %v, got %v", errs.InvalidCursor, err)
+	}
+	request = model.Request{Command: command(constants.SCAN), Params: []string{"-1"}}
+	if _, err := reqProcessor.Process(request); err != errs.InvalidCursor {
+		t.Errorf("Expected err to be %v, got %v", errs.InvalidCursor, err)
+	}
+	request = model.Request{Command: command(constants.SCAN), Params: []string{"0", "COUNT", "0"}}
+	if _, err := reqProcessor.Process(request); err != errs.SyntaxError {
+		t.Errorf("Expected err to be %v, got %v", errs.SyntaxError, err)
+	}
+	request = model.Request{Command: command(constants.SCAN), Params: []string{"0", "COUNT", "abc"}}
+	if _, err := reqProcessor.Process(request); err != errs.InvalidIntValue {
+		t.Errorf("Expected err to be %v, got %v", errs.InvalidIntValue, err)
+	}
+	request = model.Request{Command: command(constants.SCAN), Params: []string{"0", "MATCH"}}
+	if _, err := reqProcessor.Process(request); err != errs.SyntaxError {
+		t.Errorf("Expected err to be %v, got %v", errs.SyntaxError, err)
+	}
+	request = model.Request{Command: command(constants.SCAN), Params: []string{"0", "FOO", "bar"}}
+	if _, err := reqProcessor.Process(request); err != errs.SyntaxError {
+		t.Errorf("Expected err to be %v, got %v", errs.SyntaxError, err)
+	}
+}
+
+func TestProcessZScan(t *testing.T) {
+	dataStore := &mock.MockDataStore{}
+	reqProcessor := processor.RequestProcessor{DataStore: dataStore}
+	request := model.Request{Command: command(constants.ZSCAN), Params: []string{"zset", "0", "COUNT", "5"}}
+	response, err := reqProcessor.Process(request)
+	if err != nil {
+		t.Errorf("Expected err to be nil, got %v", err)
+	}
+	if !dataStore.ZScanMocked {
+		t.Errorf("Mocked ZScan Function not called")
+	}
+	reply, _ := response.Value.([]any)
+	batch, _ := reply[1].([]string)
+	if len(batch) != 2 || batch[0] != "test123" || batch[1] != "1.5" {
+		t.Errorf("Expected batch to be [test123 1.5], got %v", batch)
+	}
+	request = model.Request{Command: command(constants.ZSCAN), Params: []string{"zset", "0", "TYPE", "string"}}
+	if _, err := reqProcessor.Process(request); err != errs.SyntaxError {
+		t.Errorf("Expected err to be %v, got %v", errs.SyntaxError, err)
+	}
+}
+
+func TestProcessZAddInvalidScore(t *testing.T) {
+	dataStore := &mock.MockDataStore{}
+	reqProcessor := processor.RequestProcessor{DataStore: dataStore}
+	for _, score := range []string{"abc", "nan"} {
+		request := model.Request{Command: command(constants.ZADD), Params: []string{"test", score, "test123"}}
+		if _, err := reqProcessor.Process(request); err != errs.InvalidFloatValue {
+			t.Errorf("Expected err for %s to be %v, got %v", score, errs.InvalidFloatValue, err)
+		}
+	}
+	if dataStore.ZAddMocked {
+		t.Errorf("Mocked ZADD Function called for an invalid score")
+	}
+}
+
+func TestProcessZRank(t *testing.T) {
+	dataStore := &mock.MockDataStore{}
+	reqProcessor := processor.RequestProcessor{DataStore: dataStore}
+	request := model.Request{Command: command(constants.ZRANK), Params: []string{"zset", "test123"}}
+	response, err := reqProcessor.Process(request)
+	if err != nil {
+		t.Errorf("Expected err to be nil, got %v", err)
+	}
+	if !dataStore.ZRankMocked {
+		t.Errorf("Mocked ZRank Function not called")
+	}
+	if rank, _ := response.Value.(int); rank != 3 {
+		t.Errorf("Expected rank to be 3, got %v", response.Value)
+	}
+	request = model.Request{Command: command(constants.ZREVRANK), Params: []string{"zset", "missing"}}
+	response, _ = reqProcessor.Process(request)
+	if response.Value != nil {
+		t.Errorf("Expected rank of a missing member to be nil, got %v", response.Value)
+	}
+}
+
+func TestProcessZRangeOptions(t *testing.T) {
+	dataStore := datastore.New()
+	reqProcessor := processor.RequestProcessor{DataStore: dataStore}
+	dataStore.ZAdd("k", []model.SortedSetByte{{Score: 1, Member: []byte("a")}, {Score: 2, Member: []byte("b")}, {Score: 3, Member: []byte("c")}, {Score: 4, Member: []byte("d")}})
+	request := model.Request{Command: command(constants.ZRANGE), Params: []string{"k", "(1.5", "+inf", "BYSCORE", "LIMIT", "1", "2", "WITHSCORES"}}
+	response, err := reqProcessor.Process(request)
+	if err != nil {
+		t.Errorf("Expected err to be nil, got %v", err)
+	}
+	if data, _ := response.Value.([]model.SortedSet); !slices.Equal(data, []model.SortedSet{{Score: 3, Member: "c"}, {Score: 4, Member: "d"}}) {
+		t.Errorf("Expected c:3 d:4, got %v", response.Value)
+	}
+	request = model.Request{Command: command(constants.ZREVRANGE), Params: []string{"k", "0", "1", "WITHSCORES"}}
+	response, _ = reqProcessor.Process(request)
+	if data, _ := response.Value.([]model.SortedSet); !slices.Equal(data, []model.SortedSet{{Score: 4, Member: "d"}, {Score: 3, Member: "c"}}) {
+		t.Errorf("Expected d:4 c:3, got %v", response.Value)
+	}
+	request = model.Request{Command: command(constants.ZREVRANGEBYSCORE), Params: []string{"k", "10", "(2"}}
+	response, _ = reqProcessor.Process(request)
+	if members, _ := response.Value.([]string); !slices.Equal(members, []string{"d", "c"}) {
+		t.Errorf("Expected members to be [d c], got %v", response.Value)
+	}
+}
+
+func TestProcessZRangeByLex(t *testing.T) {
+	dataStore := datastore.New()
+	reqProcessor := processor.RequestProcessor{DataStore: dataStore}
+	dataStore.ZAdd("k", []model.SortedSetByte{{Score: 0, Member: []byte("a")}, {Score: 0, Member: []byte("b")}, {Score: 0, Member: []byte("c")}})
+	request := model.Request{Command: command(constants.ZRANGE), Params: []string{"k", "[b", "-", "bylex", "rev"}}
+	response, err := reqProcessor.Process(request)
+	if err != nil {
+		t.Errorf("Expected err to be nil, got %v", err)
+	}
+	if members, _ := response.Value.([]string); !slices.Equal(members, []string{"b", "a"}) {
+		t.Errorf("Expected members to be [b a], got %v", response.Value)
+	}
+	request = model.Request{Command: command(constants.ZRANGEBYLEX), Params: []string{"k", "(a", "+", "LIMIT", "0", "1"}}
+	response, _ = reqProcessor.Process(request)
+	if members, _ := response.Value.([]string); !slices.Equal(members, []string{"b"}) {
+		t.Errorf("Expected members to be [b], got %v", response.Value)
+	}
+}
+
+func TestProcessZRangeStore(t *testing.T) {
+	dataStore := datastore.New()
+	reqProcessor := processor.RequestProcessor{DataStore: dataStore}
+	dataStore.ZAdd("k", []model.SortedSetByte{{Score: 1, Member: []byte("a")}, {Score: 2, Member: []byte("b")}, {Score: 3, Member: []byte("c")}})
+	request := model.Request{Command: command(constants.ZRANGESTORE), Params: []string{"dst", "k", "0", "1"}}
+	response, err := reqProcessor.Process(request)
+	if err != nil {
+		t.Errorf("Expected err to be nil, got %v", err)
+	}
+	if stored, _ := response.Value.(int); stored != 2 {
+		t.Errorf("Expected stored to be 2, got %v", response.Value)
+	}
+	if data, _ := dataStore.ZRange("dst", 0, -1); !slices.Equal(data, []model.SortedSet{{Score: 1, Member: "a"}, {Score: 2, Member: "b"}}) {
+		t.Errorf("Expected dst to hold a:1 b:2, got %v", data)
+	}
+}
+
+func TestProcessZRangeInvalidOptions(t *testing.T) {
+	reqProcessor := processor.RequestProcessor{DataStore: datastore.New()}
+	request := model.Request{Command: command(constants.ZRANGE), Params: []string{"k", "0", "1", "LIMIT", "0", "1"}}
+	if _, err := reqProcessor.Process(request); err != errs.LimitWithoutBy {
+		t.Errorf("Expected err to be %v, got %v", errs.LimitWithoutBy, err)
+	}
+	request = model.Request{Command: command(constants.ZRANGE), Params: []string{"k", "a", "1"}}
+	if _, err := reqProcessor.Process(request); err != errs.InvalidIntValue {
+		t.Errorf("Expected err to be %v, got %v", errs.InvalidIntValue, err)
+	}
+	request = model.Request{Command: command(constants.ZRANGE), Params: []string{"k", "-", "+", "BYLEX", "WITHSCORES"}}
+	if _, err := reqProcessor.Process(request); err != errs.WithScoresByLex {
+		t.Errorf("Expected err to be %v, got %v", errs.WithScoresByLex, err)
+	}
+	request = model.Request{Command: command(constants.ZRANGE), Params: []string{"k", "0", "1", "BYSCORE", "BYLEX"}}
+	if _, err := reqProcessor.Process(request); err != errs.SyntaxError {
+		t.Errorf("Expected err to be %v, got %v", errs.SyntaxError, err)
+	}
+	request = model.Request{Command: command(constants.ZRANGE), Params: []string{"k", "0", "1", "BYSCORE", "LIMIT", "0"}}
+	if _, err := reqProcessor.Process(request); err != errs.SyntaxError {
+		t.Errorf("Expected err to be %v, got %v", errs.SyntaxError, err)
+	}
+	request = model.Request{Command: command(constants.ZRANGEBYSCORE), Params: []string{"k", "(a", "1"}}
+	if _, err := reqProcessor.Process(request); err != errs.InvalidScoreRange {
+		t.Errorf("Expected err to be %v, got %v", errs.InvalidScoreRange, err)
+	}
+	request = model.Request{Command: command(constants.ZRANGEBYSCORE), Params: []string{"k", "0", "1", "REV"}}
+	if _, err := reqProcessor.Process(request); err != errs.SyntaxError {
+		t.Errorf("Expected err to be %v, got %v", errs.SyntaxError, err)
+	}
+	request = model.Request{Command: command(constants.ZRANGEBYLEX), Params: []string{"k", "a", "+"}}
+	if _, err := reqProcessor.Process(request); err != errs.InvalidLexRange {
+		t.Errorf("Expected err to be %v, got %v", errs.InvalidLexRange, err)
+	}
+	request = model.Request{Command: command(constants.ZRANGESTORE), Params: []string{"dst", "k", "0", "1", "WITHSCORES"}}
+	if _, err := reqProcessor.Process(request); err != errs.SyntaxError {
+		t.Errorf("Expected err to be %v, got %v", errs.SyntaxError, err)
+	}
+	request = model.Request{Command: command(constants.ZRANGEBYLEX), Params: []string{"k", "-", "+", "WITHSCORES"}}
+	if _, err := reqProcessor.Process(request); err != errs.SyntaxError {
+		t.Errorf("Expected err to be %v, got %v", errs.SyntaxError, err)
+	}
+	request = model.Request{Command: command(constants.ZREVRANGE), Params: []string{"k", "0", "1", "LIMIT", "0", "1"}}
+	if _, err := reqProcessor.Process(request); err != errs.SyntaxError {
+		t.Errorf("Expected err to be %v, got %v", errs.SyntaxError, err)
+	}
+}
+
+func TestProcessZScore(t *testing.T) {
+	dataStore := datastore.New()
+	reqProcessor := processor.RequestProcessor{DataStore: dataStore}
+	dataStore.ZAdd("zset", []model.SortedSetByte{{Score: 1.5, Member: []byte("a")}, {Score: 2, Member: []byte("b")}})
+	request := model.Request{Command: command(constants.ZSCORE), Params: []string{"zset", "a"}}
+	response, err := reqProcessor.Process(request)
+	if err != nil {
+		t.Errorf("Expected err to be nil, got %v", err)
+	}
+	if score, _ := response.Value.(float64); score != 1.5 {
+		t.Errorf("Expected score to be 1.5, got %v", response.Value)
+	}
+	request = model.Request{Command: command(constants.ZSCORE), Params: []string{"zset", "missing"}}
+	response, _ = reqProcessor.Process(request)
+	if response.Value != nil {
+		t.Errorf("Expected score of a missing member to be nil, got %v", response.Value)
+	}
+	request = model.Request{Command: command(constants.ZMSCORE), Params: []string{"zset", "b", "missing"}}
+	response, _ = reqProcessor.Process(request)
+	if scores, _ := response.Value.([]any); len(scores) != 2 || scores[0] != 2.0 || scores[1] != nil {
+		t.Errorf("Expected scores to be [2 nil], got %v", response.Value)
+	}
+}
+
+func TestProcessZRankWithScore(t *testing.T) {
+	dataStore := datastore.New()
+	reqProcessor := processor.RequestProcessor{DataStore: dataStore}
+	dataStore.ZAdd("zset", []model.SortedSetByte{{Score: 1.5, Member: []byte("a")}, {Score: 2, Member: []byte("b")}})
+	request := model.Request{Command: command(constants.ZRANK), Params: []string{"zset", "b", "WITHSCORE"}}
+	response, err := reqProcessor.Process(request)
+	if err != nil {
+		t.Errorf("Expected err to be nil, got %v", err)
+	}
+	if reply, _ := response.Value.([]any); len(reply) != 2 || reply[0] != 1 || reply[1] != 2.0 {
+		t.Errorf("Expected rank and score to be [1 2], got %v", response.Value)
+	}
+	request = model.Request{Command: command(constants.ZRANK), Params: []string{"zset", "b", "WITHSCORES"}}
+	if _, err := reqProcessor.Process(request); err != errs.SyntaxError {
+		t.Errorf("Expected err to be %v, got %v", errs.SyntaxError, err)
+	}
+}
+
+func TestProcessZIncrBy(t *testing.T) {
+	dataStore := datastore.New()
+	reqProcessor := processor.RequestProcessor{DataStore: dataStore}
+	request := model.Request{Command: command(constants.ZINCRBY), Params: []string{"zset", "1.5", "a"}}
+	if _, err := reqProcessor.Process(request); err != nil {
+		t.Errorf("Expected err to be nil, got %v", err)
+	}
+	request = model.Request{Command: command(constants.ZINCRBY), Params: []string{"zset", "2", "a"}}
+	response, _ := reqProcessor.Process(request)
+	if score, _ := response.Value.(float64); score != 3.5 {
+		t.Errorf("Expected score to be 3.5, got %v", response.Value)
+	}
+	request = model.Request{Command: command(constants.ZINCRBY), Params: []string{"zset", "nan", "a"}}
+	if _, err := reqProcessor.Process(request); err != errs.InvalidFloatValue {
+		t.Errorf("Expected err to be %v, got %v", errs.InvalidFloatValue, err)
+	}
+}
+
+func TestProcessZCount(t *testing.T) {
+	dataStore := datastore.New()
+	reqProcessor := processor.RequestProcessor{DataStore: dataStore}
+	dataStore.ZAdd("zset", []model.SortedSetByte{{Score: 1, Member: []byte("a")}, {Score: 5, Member: []byte("b")}, {Score: 9, Member: []byte("c")}})
+	dataStore.ZAdd("lex", []model.SortedSetByte{{Score: 0, Member: []byte("a")}, {Score: 0, Member: []byte("b")}, {Score: 0, Member: []byte("c")}})
+	request := model.Request{Command: command(constants.ZCARD), Params: []string{"zset"}}
+	response, err := reqProcessor.Process(request)
+	if err != nil {
+		t.Errorf("Expected err to be nil, got %v", err)
+	}
+	if card, _ := response.Value.(int); card != 3 {
+		t.Errorf("Expected card to be 3, got %v", response.Value)
+	}
+	request = model.Request{Command: command(constants.ZCOUNT), Params: []string{"zset", "-inf", "(5"}}
+	response, _ = reqProcessor.Process(request)
+	if count, _ := response.Value.(int); count != 1 {
+		t.Errorf("Expected count to be 1, got %v", response.Value)
+	}
+	request = model.Request{Command: command(constants.ZLEXCOUNT), Params: []string{"lex", "-", "[b"}}
+	response, _ = reqProcessor.Process(request)
+	if count, _ := response.Value.(int); count != 2 {
+		t.Errorf("Expected lex count to be 2, got %v", response.Value)
+	}
+}
+
+func TestProcessZRem(t *testing.T) {
+	dataStore := datastore.New()
+	reqProcessor := processor.RequestProcessor{DataStore: dataStore}
+	dataStore.ZAdd("zset", []model.SortedSetByte{{Score: 1, Member: []byte("a")}, {Score: 2, Member: []byte("b")}, {Score: 3, Member: []byte("c")}, {Score: 4, Member: []byte("d")}})
+	request := model.Request{Command: command(constants.ZREM), Params: []string{"zset", "a", "missing"}}
+	response, err := reqProcessor.Process(request)
+	if err != nil {
+		t.Errorf("Expected err to be nil, got %v", err)
+	}
+	if removed, _ := response.Value.(int); removed != 1 {
+		t.Errorf("Expected removed to be 1, got %v", response.Value)
+	}
+	request = model.Request{Command: command(constants.ZREMRANGEBYSCORE), Params: []string{"zset", "(3", "+inf"}}
+	response, _ = reqProcessor.Process(request)
+	if removed, _ := response.Value.(int); removed != 1 {
+		t.Errorf("Expected removed by score to be 1, got %v", response.Value)
+	}
+	request = model.Request{Command: command(constants.ZREMRANGEBYRANK), Params: []string{"zset", "0", "-1"}}
+	response, _ = reqProcessor.Process(request)
+	if removed, _ := response.Value.(int); removed != 2 {
+		t.Errorf("Expected removed by rank to be 2, got %v", response.Value)
+	}
+	if card, _ := dataStore.ZCard("zset"); card != 0 {
+		t.Errorf("Expected the sorted set to be empty, got %d members", card)
+	}
+}
+
+func TestProcessZAddOptions(t *testing.T) {
+	dataStore := datastore.New()
+	reqProcessor := processor.RequestProcessor{DataStore: dataStore}
+	dataStore.ZAdd("board", []model.SortedSetByte{{Score: 1.5, Member: []byte("a")}})
+	request := model.Request{Command: command(constants.ZADD), Params: []string{"board", "gt", "CH", "INCR", "2", "a"}}
+	response, err := reqProcessor.Process(request)
+	if err != nil {
+		t.Errorf("Expected err to be nil, got %v", err)
+	}
+	if score, _ := response.Value.(float64); score != 3.5 {
+		t.Errorf("Expected score to be 3.5, got %v", response.Value)
+	}
+	// GT rejects the lower score, INCR replies nil
+	request = model.Request{Command: command(constants.ZADD), Params: []string{"board", "GT", "INCR", "-1", "a"}}
+	response, _ = reqProcessor.Process(request)
+	if response.Value != nil {
+		t.Errorf("Expected score to be nil, got %v", response.Value)
+	}
+	// XX updates a, doesn't add b, and CH counts the update
+	request = model.Request{Command: command(constants.ZADD), Params: []string{"board", "XX", "CH", "1", "a", "2", "b"}}
+	response, _ = reqProcessor.Process(request)
+	if changed, _ := response.Value.(int); changed != 1 {
+		t.Errorf("Expected changed to be 1, got %v", response.Value)
+	}
+	request = model.Request{Command: command(constants.ZADD), Params: []string{"board", "NX", "9", "a", "2", "b"}}
+	response, _ = reqProcessor.Process(request)
+	if added, _ := response.Value.(int); added != 1 {
+		t.Errorf("Expected added to be 1, got %v", response.Value)
+	}
+	if data, _ := dataStore.ZRange("board", 0, -1); !slices.Equal(data, []model.SortedSet{{Score: 1, Member: "a"}, {Score: 2, Member: "b"}}) {
+		t.Errorf("Expected board to hold a:1 b:2, got %v", data)
+	}
+}
+
+func TestProcessZAddInvalidOptions(t *testing.T) {
+	dataStore := datastore.New()
+	reqProcessor := processor.RequestProcessor{DataStore: dataStore}
+	request := model.Request{Command: command(constants.ZADD), Params: []string{"board", "NX", "XX", "1", "a"}}
+	if _, err := reqProcessor.Process(request); err != errs.ZAddNXXXIncompatible {
+		t.Errorf("Expected err to be %v, got %v", errs.ZAddNXXXIncompatible, err)
+	}
+	request = model.Request{Command: command(constants.ZADD), Params: []string{"board", "NX", "GT", "1", "a"}}
+	if _, err := reqProcessor.Process(request); err != errs.ZAddGTLTNXIncompatible {
+		t.Errorf("Expected err to be %v, got %v", errs.ZAddGTLTNXIncompatible, err)
+	}
+	request = model.Request{Command: command(constants.ZADD), Params: []string{"board", "GT", "LT", "1", "a"}}
+	if _, err := reqProcessor.Process(request); err != errs.ZAddGTLTNXIncompatible {
+		t.Errorf("Expected err to be %v, got %v", errs.ZAddGTLTNXIncompatible, err)
+	}
+	request = model.Request{Command: command(constants.ZADD), Params: []string{"board", "INCR", "1", "a", "2", "b"}}
+	if _, err := reqProcessor.Process(request); err != errs.ZAddIncrSinglePair {
+		t.Errorf("Expected err to be %v, got %v", errs.ZAddIncrSinglePair, err)
+	}
+	request = model.Request{Command: command(constants.ZADD), Params: []string{"board", "NX", "CH"}}
+	if _, err := reqProcessor.Process(request); err != errs.SyntaxError {
+		t.Errorf("Expected err to be %v, got %v", errs.SyntaxError, err)
+	}
+	request = model.Request{Command: command(constants.ZADD), Params: []string{"board", "1", "a", "2"}}
+	if _, err := reqProcessor.Process(request); err != errs.SyntaxError {
+		t.Errorf("Expected err to be %v, got %v", errs.SyntaxError, err)
+	}
+	if card, _ := dataStore.ZCard("board"); card != 0 {
+		t.Errorf("Expected nothing to be added for invalid options, got %d members", card)
+	}
+}
+
+func TestProcessZSetOperations(t *testing.T) {
+	dataStore := datastore.New()
+	reqProcessor := processor.RequestProcessor{DataStore: dataStore}
+	dataStore.ZAdd("a", []model.SortedSetByte{{Score: 1, Member: []byte("x")}, {Score: 2, Member: []byte("y")}})
+	dataStore.ZAdd("b", []model.SortedSetByte{{Score: 3, Member: []byte("y")}, {Score: 4, Member: []byte("z")}})
+	request := model.Request{Command: command(constants.ZUNIONSTORE), Params: []string{"dst", "2", "a", "b", "WEIGHTS", "2", "3", "AGGREGATE", "min"}}
+	response, err := reqProcessor.Process(request)
+	if err != nil {
+		t.Errorf("Expected err to be nil, got %v", err)
+	}
+	if stored, _ := response.Value.(int); stored != 3 {
+		t.Errorf("Expected stored to be 3, got %v", response.Value)
+	}
+	if data, _ := dataStore.ZRange("dst", 0, -1); !slices.Equal(data, []model.SortedSet{{Score: 2, Member: "x"}, {Score: 4, Member: "y"}, {Score: 12, Member: "z"}}) {
+		t.Errorf("Expected dst to hold x:2 y:4 z:12, got %v", data)
+	}
+	request = model.Request{Command: command(constants.ZINTER), Params: []string{"2", "a", "b", "WITHSCORES"}}
+	response, _ = reqProcessor.Process(request)
+	if data, _ := response.Value.([]model.SortedSet); !slices.Equal(data, []model.SortedSet{{Score: 5, Member: "y"}}) {
+		t.Errorf("Expected y:5, got %v", response.Value)
+	}
+	request = model.Request{Command: command(constants.ZDIFF), Params: []string{"2", "a", "b"}}
+	response, _ = reqProcessor.Process(request)
+	if data, _ := response.Value.([]string); !slices.Equal(data, []string{"x"}) {
+		t.Errorf("Expected x, got %v", response.Value)
+	}
+	request = model.Request{Command: command(constants.ZINTERCARD), Params: []string{"2", "a", "dst", "LIMIT", "1"}}
+	response, _ = reqProcessor.Process(request)
+	if card, _ := response.Value.(int); card != 1 {
+		t.Errorf("Expected card to be 1, got %v", response.Value)
+	}
+	request = model.Request{Command: command(constants.ZINTERCARD), Params: []string{"2", "a", "missing"}}
+	response, _ = reqProcessor.Process(request)
+	if card, _ := response.Value.(int); card != 0 {
+		t.Errorf("Expected card to be 0, got %v", response.Value)
+	}
+}
+
+func TestProcessZSetOperationsInvalidOptions(t *testing.T) {
+	dataStore := datastore.New()
+	reqProcessor := processor.RequestProcessor{DataStore: dataStore}
+	request := model.Request{Command: command(constants.ZUNION), Params: []string{"0", "a"}}
+	if _, err := reqProcessor.Process(request); err != errs.NoInputKeys {
+		t.Errorf("Expected err to be %v, got %v", errs.NoInputKeys, err)
+	}
+	request = model.Request{Command: command(constants.ZUNION), Params: []string{"x", "a"}}
+	if _, err := reqProcessor.Process(request); err != errs.InvalidIntValue {
+		t.Errorf("Expected err to be %v, got %v", errs.InvalidIntValue, err)
+	}
+	request = model.Request{Command: command(constants.ZUNION), Params: []string{"3", "a", "b"}}
+	if _, err := reqProcessor.Process(request); err != errs.SyntaxError {
+		t.Errorf("Expected err to be %v, got %v", errs.SyntaxError, err)
+	}
+	request = model.Request{Command: command(constants.ZUNION), Params: []string{"2", "a", "b", "WEIGHTS", "1"}}
+	if _, err := reqProcessor.Process(request); err != errs.SyntaxError {
+		t.Errorf("Expected err to be %v, got %v", errs.SyntaxError, err)
+	}
+	request = model.Request{Command: command(constants.ZUNION), Params: []string{"2", "a", "b", "WEIGHTS", "1", "x"}}
+	if _, err := reqProcessor.Process(request); err != errs.InvalidWeight {
+		t.Errorf("Expected err to be %v, got %v", errs.InvalidWeight, err)
+	}
+	request = model.Request{Command: command(constants.ZINTER), Params: []string{"1", "a", "AGGREGATE", "AVG"}}
+	if _, err := reqProcessor.Process(request); err != errs.SyntaxError {
+		t.Errorf("Expected err to be %v, got %v", errs.SyntaxError, err)
+	}
+	request = model.Request{Command: command(constants.ZDIFF), Params: []string{"1", "a", "WEIGHTS", "1"}}
+	if _, err := reqProcessor.Process(request); err != errs.SyntaxError {
+		t.Errorf("Expected err to be %v, got %v", errs.SyntaxError, err)
+	}
+	request = model.Request{Command: command(constants.ZDIFFSTORE), Params: []string{"dst", "1", "a", "WITHSCORES"}}
+	if _, err := reqProcessor.Process(request); err != errs.SyntaxError {
+		t.Errorf("Expected err to be %v, got %v", errs.SyntaxError, err)
+	}
+	request = model.Request{Command: command(constants.ZINTERCARD), Params: []string{"1", "a", "LIMIT", "-1"}}
+	if _, err := reqProcessor.Process(request); err != errs.NegativeLimit {
+		t.Errorf("Expected err to be %v, got %v", errs.NegativeLimit, err)
+	}
+}
+
+func TestProcessZPop(t *testing.T) {
+	dataStore := datastore.New()
+	reqProcessor := processor.RequestProcessor{DataStore: dataStore}
+	dataStore.ZAdd("board", []model.SortedSetByte{{Score: 1, Member: []byte("a")}, {Score: 2, Member: []byte("b")}, {Score: 3, Member: []byte("c")}, {Score: 4, Member: []byte("d")}})
+	request := model.Request{Command: command(constants.ZPOPMIN), Params: []string{"board"}}
+	response, err := reqProcessor.Process(request)
+	if err != nil {
+		t.Errorf("Expected err to be nil, got %v", err)
+	}
+	reply, _ := response.Value.([]any)
+	if len(reply) != 2 || string(reply[0].([]byte)) != "a" || reply[1] != 1.0 {
+		t.Errorf("Expected a 1, got %v", response.Value)
+	}
+	request = model.Request{Command: command(constants.ZPOPMAX), Params: []string{"board", "2"}}
+	response, _ = reqProcessor.Process(request)
+	if data, _ := response.Value.([]model.SortedSet); !slices.Equal(data, []model.SortedSet{{Score: 4, Member: "d"}, {Score: 3, Member: "c"}}) {
+		t.Errorf("Expected d:4 c:3, got %v", response.Value)
+	}
+	request = model.Request{Command: command(constants.BZPOPMIN), Params: []string{"missing", "0.05"}}
+	response, _ = reqProcessor.Process(request)
+	if response.Value != (model.NullArray{}) {
+		t.Errorf("Expected a null array on timeout, got %v", response.Value)
+	}
+	request = model.Request{Command: command(constants.BZPOPMAX), Params: []string{"missing", "board", "1"}}
+	response, _ = reqProcessor.Process(request)
+	reply, _ = response.Value.([]any)
+	if len(reply) != 3 || string(reply[0].([]byte)) != "board" || string(reply[1].([]byte)) != "b" || reply[2] != 2.0 {
+		t.Errorf("Expected board b 2, got %v", response.Value)
+	}
+	if keys, _ := dataStore.Keys("board"); len(keys) != 0 {
+		t.Errorf("Expected board to be deleted once empty, got %v", keys)
+	}
+
+	dataStore.ZAdd("other", []model.SortedSetByte{{Score: 1, Member: []byte("x")}, {Score: 2, Member: []byte("y")}})
+	request = model.Request{Command: command(constants.ZMPOP), Params: []string{"2", "board", "other", "max", "COUNT", "3"}}
+	response, _ = reqProcessor.Process(request)
+	reply, _ = response.Value.([]any)
+	if len(reply) != 2 || string(reply[0].([]byte)) != "other" {
+		t.Fatalf("Expected other and its members, got %v", response.Value)
+	}
+	pairs, _ := reply[1].([]any)
+	if len(pairs) != 2 {
+		t.Fatalf("Expected 2 members, got %v", reply[1])
+	}
+	if pair, _ := pairs[0].([]any); string(pair[0].([]byte)) != "y" || pair[1] != 2.0 {
+		t.Errorf("Expected y 2 first, got %v", pairs[0])
+	}
+	request = model.Request{Command: command(constants.BZMPOP), Params: []string{"0.05", "1", "missing", "MIN"}}
+	response, _ = reqProcessor.Process(request)
+	if response.Value != (model.NullArray{}) {
+		t.Errorf("Expected a null array on timeout, got %v", response.Value)
+	}
+
+	dataStore.ZAdd("single", []model.SortedSetByte{{Score: 1.5, Member: []byte("a")}})
+	request = model.Request{Command: command(constants.ZRANDMEMBER), Params: []string{"single"}}
+	response, _ = reqProcessor.Process(request)
+	if member, _ := response.Value.([]byte); string(member) != "a" {
+		t.Errorf("Expected a, got %v", response.Value)
+	}
+	request = model.Request{Command: command(constants.ZRANDMEMBER), Params: []string{"single", "-2", "WITHSCORES"}}
+	response, _ = reqProcessor.Process(request)
+	if data, _ := response.Value.([]model.SortedSet); !slices.Equal(data, []model.SortedSet{{Score: 1.5, Member: "a"}, {Score: 1.5, Member: "a"}}) {
+		t.Errorf("Expected a:1.5 twice, got %v", response.Value)
+	}
+}
+
+func TestProcessZPopInvalidOptions(t *testing.T) {
+	dataStore := datastore.New()
+	reqProcessor := processor.RequestProcessor{DataStore: dataStore}
+	request := model.Request{Command: command(constants.ZPOPMIN), Params: []string{"board", "-1"}}
+	if _, err := reqProcessor.Process(request); err != errs.NegativeCount {
+		t.Errorf("Expected err to be %v, got %v", errs.NegativeCount, err)
+	}
+	request = model.Request{Command: command(constants.BZPOPMIN), Params: []string{"board", "-1"}}
+	if _, err := reqProcessor.Process(request); err != errs.NegativeTimeout {
+		t.Errorf("Expected err to be %v, got %v", errs.NegativeTimeout, err)
+	}
+	request = model.Request{Command: command(constants.BZPOPMIN), Params: []string{"board", "abc"}}
+	if _, err := reqProcessor.Process(request); err != errs.InvalidTimeout {
+		t.Errorf("Expected err to be %v, got %v", errs.InvalidTimeout, err)
+	}
+	request = model.Request{Command: command(constants.ZMPOP), Params: []string{"1", "board"}}
+	if _, err := reqProcessor.Process(request); err != errs.SyntaxError {
+		t.Errorf("Expected err to be %v, got %v", errs.SyntaxError, err)
+	}
+	request = model.Request{Command: command(constants.ZMPOP), Params: []string{"1", "board", "MIN", "COUNT", "0"}}
+	if _, err := reqProcessor.Process(request); err != errs.CountNotPositive {
+		t.Errorf("Expected err to be %v, got %v", errs.CountNotPositive, err)
+	}
+	request = model.Request{Command: command(constants.ZRANDMEMBER), Params: []string{"board", "1", "WITHSCORE"}}
+	if _, err := reqProcessor.Process(request); err != errs.SyntaxError {
+		t.Errorf("Expected err to be %v, got %v", errs.SyntaxError, err)
+	}
+}
+
+func TestProcessList(t *testing.T) {
+	dataStore := datastore.New()
+	reqProcessor := processor.RequestProcessor{DataStore: dataStore}
+	request := model.Request{Command: command(constants.LPUSH), Params: []string{"list", "a", "b"}}
+	response, err := reqProcessor.Process(request)
+	if err != nil {
+		t.Errorf("Expected err to be nil, got %v", err)
+	}
+	if length, _ := response.Value.(int); length != 2 {
+		t.Errorf("Expected length to be 2, got %v", response.Value)
+	}
+	request = model.Request{Command: command(constants.RPUSH), Params: []string{"list", "c"}}
+	response, _ = reqProcessor.Process(request)
+	if length, _ := response.Value.(int); length != 3 {
+		t.Errorf("Expected length to be 3, got %v", response.Value)
+	}
+	request = model.Request{Command: command(constants.LPOP), Params: []string{"list"}}
+	response, _ = reqProcessor.Process(request)
+	if element, _ := response.Value.([]byte); string(element) != "b" {
+		t.Errorf("Expected b, got %v", response.Value)
+	}
+	request = model.Request{Command: command(constants.RPUSH), Params: []string{"list", "a", "b", "a"}}
+	reqProcessor.Process(request)
+	request = model.Request{Command: command(constants.RPOP), Params: []string{"list", "2"}}
+	response, _ = reqProcessor.Process(request)
+	if elements, _ := response.Value.([]string); !slices.Equal(elements, []string{"a", "b"}) {
+		t.Errorf("Expected a b, got %v", response.Value)
+	}
+	request = model.Request{Command: command(constants.LPOP), Params: []string{"missing", "3"}}
+	response, _ = reqProcessor.Process(request)
+	if response.Value != (model.NullArray{}) {
+		t.Errorf("Expected a null array for a missing key, got %v", response.Value)
+	}
+	request = model.Request{Command: command(constants.RPOP), Params: []string{"missing"}}
+	response, _ = reqProcessor.Process(request)
+	if response.Value != nil {
+		t.Errorf("Expected nil for a missing key, got %v", response.Value)
+	}
+	request = model.Request{Command: command(constants.LLEN), Params: []string{"list"}}
+	response, _ = reqProcessor.Process(request)
+	if length, _ := response.Value.(int); length != 3 {
+		t.Errorf("Expected length to be 3, got %v", response.Value)
+	}
+	request = model.Request{Command: command(constants.LINDEX), Params: []string{"list", "-2"}}
+	response, _ = reqProcessor.Process(request)
+	if element, _ := response.Value.([]byte); string(element) != "c" {
+		t.Errorf("Expected c, got %v", response.Value)
+	}
+	request = model.Request{Command: command(constants.LSET), Params: []string{"list", "1", "x"}}
+	response, _ = reqProcessor.Process(request)
+	if response.Value != "OK" {
+		t.Errorf("Expected OK, got %v", response.Value)
+	}
+	request = model.Request{Command: command(constants.LREM), Params: []string{"list", "-1", "a"}}
+	response, _ = reqProcessor.Process(request)
+	if removed, _ := response.Value.(int); removed != 1 {
+		t.Errorf("Expected removed to be 1, got %v", response.Value)
+	}
+	request = model.Request{Command: command(constants.LINSERT), Params: []string{"list", "after", "a", "b"}}
+	response, _ = reqProcessor.Process(request)
+	if length, _ := response.Value.(int); length != 3 {
+		t.Errorf("Expected length to be 3, got %v", response.Value)
+	}
+	request = model.Request{Command: command(constants.LTRIM), Params: []string{"list", "1", "-1"}}
+	response, _ = reqProcessor.Process(request)
+	if response.Value != "OK" {
+		t.Errorf("Expected OK, got %v", response.Value)
+	}
+	request = model.Request{Command: command(constants.LRANGE), Params: []string{"list", "0", "-1"}}
+	response, _ = reqProcessor.Process(request)
+	if elements, _ := response.Value.([]string); !slices.Equal(elements, []string{"b", "x"}) {
+		t.Errorf("Expected b x, got %v", response.Value)
+	}
+}
+
+func TestProcessListInvalidOptions(t *testing.T) {
+	dataStore := datastore.New()
+	reqProcessor := processor.RequestProcessor{DataStore: dataStore}
+	request := model.Request{Command: command(constants.LPOP), Params: []string{"list", "-1"}}
+	if _, err := reqProcessor.Process(request); err != errs.NegativeCount {
+		t.Errorf("Expected err to be %v, got %v", errs.NegativeCount, err)
+	}
+	request = model.Request{Command: command(constants.LPOP), Params: []string{"list", "1", "2"}}
+	if _, err := reqProcessor.Process(request); err != errs.SyntaxError {
+		t.Errorf("Expected err to be %v, got %v", errs.SyntaxError, err)
+	}
+	request = model.Request{Command: command(constants.LRANGE), Params: []string{"list", "a", "1"}}
+	if _, err := reqProcessor.Process(request); err != errs.InvalidIntValue {
+		t.Errorf("Expected err to be %v, got %v", errs.InvalidIntValue, err)
+	}
+	request = model.Request{Command: command(constants.LINSERT), Params: []string{"list", "middle", "a", "b"}}
+	if _, err := reqProcessor.Process(request); err != errs.SyntaxError {
+		t.Errorf("Expected err to be %v, got %v", errs.SyntaxError, err)
+	}
+}
+
+func TestProcessBlockingList(t *testing.T) {
+	dataStore := datastore.New()
+	reqProcessor := processor.RequestProcessor{DataStore: dataStore}
+	dataStore.Push("a", []string{"x", "y", "z"}, false)
+	request := model.Request{Command: command(constants.BLPOP), Params: []string{"missing", "a", "1.5"}}
+	response, err := reqProcessor.Process(request)
+	if err != nil {
+		t.Errorf("Expected err to be nil, got %v", err)
+	}
+	reply, _ := response.Value.([]any)
+	if len(reply) != 2 || string(reply[0].([]byte)) != "a" || string(reply[1].([]byte)) != "x" {
+		t.Errorf("Expected a x, got %v", response.Value)
+	}
+	request = model.Request{Command: command(constants.BRPOP), Params: []string{"missing", "0.05"}}
+	response, _ = reqProcessor.Process(request)
+	if response.Value != (model.NullArray{}) {
+		t.Errorf("Expected a null array on timeout, got %v", response.Value)
+	}
+	request = model.Request{Command: command(constants.LMOVE), Params: []string{"a", "b", "right", "LEFT"}}
+	response, _ = reqProcessor.Process(request)
+	if element, _ := response.Value.([]byte); string(element) != "z" {
+		t.Errorf("Expected z, got %v", response.Value)
+	}
+	request = model.Request{Command: command(constants.LMOVE), Params: []string{"missing", "b", "LEFT", "LEFT"}}
+	response, _ = reqProcessor.Process(request)
+	if element, _ := response.Value.([]byte); element != nil {
+		t.Errorf("Expected nil for a missing source, got %v", response.Value)
+	}
+	request = model.Request{Command: command(constants.BLMOVE), Params: []string{"a", "b", "LEFT", "RIGHT", "0.5"}}
+	response, _ = reqProcessor.Process(request)
+	if element, _ := response.Value.([]byte); string(element) != "y" {
+		t.Errorf("Expected y, got %v", response.Value)
+	}
+	request = model.Request{Command: command(constants.BLMOVE), Params: []string{"a", "b", "LEFT", "RIGHT", "0.05"}}
+	response, _ = reqProcessor.Process(request)
+	if element, _ := response.Value.([]byte); element != nil {
+		t.Errorf("Expected nil on timeout, got %v", response.Value)
+	}
+	if elements, _ := dataStore.LRange("b", 0, -1); !slices.Equal(elements, []string{"z", "y"}) {
+		t.Errorf("Expected b to hold z y, got %v", elements)
+	}
+}
+
+func TestProcessBlockingListInvalidOptions(t *testing.T) {
+	dataStore := datastore.New()
+	reqProcessor := processor.RequestProcessor{DataStore: dataStore}
+	request := model.Request{Command: command(constants.BLPOP), Params: []string{"a", "-1"}}
+	if _, err := reqProcessor.Process(request); err != errs.NegativeTimeout {
+		t.Errorf("Expected err to be %v, got %v", errs.NegativeTimeout, err)
+	}
+	request = model.Request{Command: command(constants.BRPOP), Params: []string{"a", "soon"}}
+	if _, err := reqProcessor.Process(request); err != errs.InvalidTimeout {
+		t.Errorf("Expected err to be %v, got %v", errs.InvalidTimeout, err)
+	}
+	request = model.Request{Command: command(constants.LMOVE), Params: []string{"a", "b", "UP", "LEFT"}}
+	if _, err := reqProcessor.Process(request); err != errs.SyntaxError {
+		t.Errorf("Expected err to be %v, got %v", errs.SyntaxError, err)
+	}
+	request = model.Request{Command: command(constants.BLMOVE), Params: []string{"a", "b", "LEFT", "LEFT", "x"}}
+	if _, err := reqProcessor.Process(request); err != errs.InvalidTimeout {
+		t.Errorf("Expected err to be %v, got %v", errs.InvalidTimeout, err)
+	}
+}
+
+func TestProcessHash(t *testing.T) {
+	dataStore := datastore.New()
+	reqProcessor := processor.RequestProcessor{DataStore: dataStore}
+	request := model.Request{Command: command(constants.HSET), Params: []string{"user", "name", "a", "city", "b"}}
+	response, err := reqProcessor.Process(request)
+	if err != nil {
+		t.Errorf("Expected err to be nil, got %v", err)
+	}
+	if added, _ := response.Value.(int); added != 2 {
+		t.Errorf("Expected added to be 2, got %v", response.Value)
+	}
+	request = model.Request{Command: command(constants.HSETNX), Params: []string{"user", "name", "z"}}
+	response, _ = reqProcessor.Process(request)
+	if set, _ := response.Value.(int); set != 0 {
+		t.Errorf("Expected set to be 0, got %v", response.Value)
+	}
+	request = model.Request{Command: command(constants.HGET), Params: []string{"user", "name"}}
+	response, _ = reqProcessor.Process(request)
+	if value, _ := response.Value.([]byte); string(value) != "a" {
+		t.Errorf("Expected a, got %v", response.Value)
+	}
+	request = model.Request{Command: command(constants.HMGET), Params: []string{"user", "city", "missing"}}
+	response, _ = reqProcessor.Process(request)
+	reply, _ := response.Value.([]any)
+	if len(reply) != 2 || string(reply[0].([]byte)) != "b" || reply[1].([]byte) != nil {
+		t.Errorf("Expected b and nil, got %v", response.Value)
+	}
+	request = model.Request{Command: command(constants.HDEL), Params: []string{"user", "city", "missing"}}
+	response, _ = reqProcessor.Process(request)
+	if removed, _ := response.Value.(int); removed != 1 {
+		t.Errorf("Expected removed to be 1, got %v", response.Value)
+	}
+	request = model.Request{Command: command(constants.HGETALL), Params: []string{"user"}}
+	response, _ = reqProcessor.Process(request)
+	fields, _ := response.Value.(model.Map)
+	if len(fields) != 1 || fields[0].Key != "name" || string(fields[0].Value.([]byte)) != "a" {
+		t.Errorf("Expected name a, got %v", response.Value)
+	}
+	request = model.Request{Command: command(constants.HEXISTS), Params: []string{"user", "name"}}
+	response, _ = reqProcessor.Process(request)
+	if exists, _ := response.Value.(int); exists != 1 {
+		t.Errorf("Expected exists to be 1, got %v", response.Value)
+	}
+	request = model.Request{Command: command(constants.HLEN), Params: []string{"user"}}
+	response, _ = reqProcessor.Process(request)
+	if length, _ := response.Value.(int); length != 1 {
+		t.Errorf("Expected length to be 1, got %v", response.Value)
+	}
+	request = model.Request{Command: command(constants.HSTRLEN), Params: []string{"user", "name"}}
+	response, _ = reqProcessor.Process(request)
+	if length, _ := response.Value.(int); length != 1 {
+		t.Errorf("Expected length to be 1, got %v", response.Value)
+	}
+	request = model.Request{Command: command(constants.HKEYS), Params: []string{"user"}}
+	response, _ = reqProcessor.Process(request)
+	if keys, _ := response.Value.([]string); !slices.Equal(keys, []string{"name"}) {
+		t.Errorf("Expected name, got %v", response.Value)
+	}
+	request = model.Request{Command: command(constants.HVALS), Params: []string{"user"}}
+	response, _ = reqProcessor.Process(request)
+	if values, _ := response.Value.([]string); !slices.Equal(values, []string{"a"}) {
+		t.Errorf("Expected a, got %v", response.Value)
+	}
+	request = model.Request{Command: command(constants.HINCRBY), Params: []string{"user", "visits", "-3"}}
+	response, _ = reqProcessor.Process(request)
+	if value, _ := response.Value.(int64); value != -3 {
+		t.Errorf("Expected value to be -3, got %v", response.Value)
+	}
+	request = model.Request{Command: command(constants.HINCRBYFLOAT), Params: []string{"user", "balance", "1.5"}}
+	reqProcessor.Process(request)
+	request = model.Request{Command: command(constants.HINCRBYFLOAT), Params: []string{"user", "balance", "0.25"}}
+	response, _ = reqProcessor.Process(request)
+	if value, _ := response.Value.([]byte); string(value) != "1.75" {
+		t.Errorf("Expected value to be 1.75, got %v", response.Value)
+	}
+}
+
+func TestProcessHRandField(t *testing.T) {
+	dataStore := datastore.New()
+	reqProcessor := processor.RequestProcessor{DataStore: dataStore}
+	dataStore.HSet("user", []model.FieldValue{{Field: "name", Value: "a"}})
+	request := model.Request{Command: command(constants.HRANDFIELD), Params: []string{"user"}}
+	response, err := reqProcessor.Process(request)
+	if err != nil {
+		t.Errorf("Expected err to be nil, got %v", err)
+	}
+	if field, _ := response.Value.([]byte); string(field) != "name" {
+		t.Errorf("Expected name, got %v", response.Value)
+	}
+	request = model.Request{Command: command(constants.HRANDFIELD), Params: []string{"missing"}}
+	response, _ = reqProcessor.Process(request)
+	if response.Value != nil {
+		t.Errorf("Expected nil for a missing key, got %v", response.Value)
+	}
+	request = model.Request{Command: command(constants.HRANDFIELD), Params: []string{"user", "-3", "withvalues"}}
+	response, _ = reqProcessor.Process(request)
+	if fields, _ := response.Value.([]model.FieldValue); len(fields) != 3 || fields[2] != (model.FieldValue{Field: "name", Value: "a"}) {
+		t.Errorf("Expected name a three times, got %v", response.Value)
+	}
+	request = model.Request{Command: command(constants.HRANDFIELD), Params: []string{"user", "2"}}
+	response, _ = reqProcessor.Process(request)
+	if fields, _ := response.Value.([]string); !slices.Equal(fields, []string{"name"}) {
+		t.Errorf("Expected name once, got %v", response.Value)
+	}
+	request = model.Request{Command: command(constants.HSCAN), Params: []string{"user", "0", "MATCH", "n*"}}
+	response, _ = reqProcessor.Process(request)
+	reply, _ := response.Value.([]any)
+	if len(reply) != 2 || string(reply[0].([]byte)) != "0" {
+		t.Fatalf("Expected cursor 0 and a batch, got %v", response.Value)
+	}
+	if batch, _ := reply[1].([]string); !slices.Equal(batch, []string{"name", "a"}) {
+		t.Errorf("Expected name a, got %v", reply[1])
+	}
+}
+
+func TestProcessHashInvalidOptions(t *testing.T) {
+	dataStore := datastore.New()
+	reqProcessor := processor.RequestProcessor{DataStore: dataStore}
+	request := model.Request{Command: command(constants.HSET), Params: []string{"user", "name", "a", "city"}}
+	if _, err := reqProcessor.Process(request); err != errs.MinReqParams {
+		t.Errorf("Expected err to be %v, got %v", errs.MinReqParams, err)
+	}
+	request = model.Request{Command: command(constants.HINCRBY), Params: []string{"user", "visits", "1.5"}}
+	if _, err := reqProcessor.Process(request); err != errs.InvalidIntValue {
+		t.Errorf("Expected err to be %v, got %v", errs.InvalidIntValue, err)
+	}
+	request = model.Request{Command: command(constants.HINCRBYFLOAT), Params: []string{"user", "balance", "nan"}}
+	if _, err := reqProcessor.Process(request); err != errs.InvalidFloatValue {
+		t.Errorf("Expected err to be %v, got %v", errs.InvalidFloatValue, err)
+	}
+	request = model.Request{Command: command(constants.HRANDFIELD), Params: []string{"user", "1", "WITHSCORES"}}
+	if _, err := reqProcessor.Process(request); err != errs.SyntaxError {
+		t.Errorf("Expected err to be %v, got %v", errs.SyntaxError, err)
+	}
+	request = model.Request{Command: command(constants.HSCAN), Params: []string{"user", "-1"}}
+	if _, err := reqProcessor.Process(request); err != errs.InvalidCursor {
+		t.Errorf("Expected err to be %v, got %v", errs.InvalidCursor, err)
 	}
 }
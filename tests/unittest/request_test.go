@@ -1,6 +1,7 @@
 package unittest
 
 import (
+	"reflect"
 	"testing"
 
 	"github.com/saurabhy27/redis-database/constants"
@@ -168,3 +169,58 @@ func TestZRangeInvalidParseProtocol(t *testing.T) {
 		t.Errorf("Expected err to be %v, got %v", errs.MinReqParams, err)
 	}
 }
+
+func TestSplitArgsQuoted(t *testing.T) {
+	cases := []struct {
+		input string
+		exp   []string
+	}{
+		{`SET greeting "hello world"`, []string{"SET", "greeting", "hello world"}},
+		{`SET  greeting   'it\'s here' `, []string{"SET", "greeting", "it's here"}},
+		{`SET k "line\nnext \x41\"" `, []string{"SET", "k", "line\nnext A\""}},
+		{`SET k ""`, []string{"SET", "k", ""}},
+		{"   ", []string{}},
+	}
+	for _, c := range cases {
+		args, err := request.SplitArgs(c.input)
+		if err != nil {
+			t.Errorf("Expected err to be nil, got %v", err)
+		}
+		if !reflect.DeepEqual(args, c.exp) {
+			t.Errorf("Expected %q to be split into %q, got %q", c.input, c.exp, args)
+		}
+	}
+}
+
+func TestSplitArgsUnbalancedQuotes(t *testing.T) {
+	for _, input := range []string{`SET k "hello`, `SET k 'hello`, `SET k "hello"world`} {
+		_, err := request.SplitArgs(input)
+		if err != errs.UnbalancedQuotes {
+			t.Errorf("Expected err for %q to be %v, got %v", input, errs.UnbalancedQuotes, err)
+		}
+	}
+}
+
+func TestCaseInsensitiveParseProtocol(t *testing.T) {
+	command, err := request.ParseProtocol("get test")
+	if err != nil {
+		t.Errorf("Expected err to be nil, got %v", err)
+	}
+	if command.Command.Cmd != constants.GET {
+		t.Errorf("Expected CMD to be %s, got %s", constants.GET, command.Command.Cmd)
+	}
+}
+
+func TestExactArityParseProtocol(t *testing.T) {
+	_, err := request.ParseProtocol("GET test test")
+	if err != errs.MinReqParams {
+		t.Errorf("Expected err to be %v, got %v", errs.MinReqParams, err)
+	}
+}
+
+func TestUnknownParseProtocol(t *testing.T) {
+	_, err := request.ParseProtocol("NOSUCHCMD test")
+	if err != errs.InvalidCommand {
+		t.Errorf("Expected err to be %v, got %v", errs.InvalidCommand, err)
+	}
+}
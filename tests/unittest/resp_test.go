@@ -0,0 +1,145 @@
+package unittest
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/saurabhy27/redis-database/errs"
+	"github.com/saurabhy27/redis-database/model"
+	"github.com/saurabhy27/redis-database/resp"
+)
+
+func TestReadMultiBulk(t *testing.T) {
+	input := "*3\r\n$3\r\nSET\r\n$4\r\ntest\r\n$11\r\nhello world\r\n"
+	// one byte per read, like a command split over many tcp segments
+	reader := resp.NewReader(iotest.OneByteReader(strings.NewReader(input)), 1024)
+	multiBulk, err := reader.IsMultiBulk()
+	if err != nil || !multiBulk {
+		t.Errorf("Expected multibulk, got %v %v", multiBulk, err)
+	}
+	args, err := reader.ReadMultiBulk()
+	if err != nil {
+		t.Errorf("Expected err to be nil, got %v", err)
+	}
+	if len(args) != 3 {
+		t.Fatalf("Expected args to be 3, got %d", len(args))
+	}
+	if args[2] != "hello world" {
+		t.Errorf("Expected args[2] to be hello world, got %s", args[2])
+	}
+}
+
+func TestReadMultiBulkLargeValue(t *testing.T) {
+	value := strings.Repeat("x", 300*1024)
+	input := fmt.Sprintf("*2\r\n$4\r\nECHO\r\n$%d\r\n%s\r\n", len(value), value)
+	reader := resp.NewReader(strings.NewReader(input), 512*1024)
+	args, err := reader.ReadMultiBulk()
+	if err != nil {
+		t.Errorf("Expected err to be nil, got %v", err)
+	}
+	if len(args) != 2 || args[1] != value {
+		t.Errorf("Expected the %d bytes value to be read back", len(value))
+	}
+}
+
+func TestReadMultiBulkInvalid(t *testing.T) {
+	cases := []struct {
+		input string
+		err   error
+	}{
+		{"*2\r\n$3\r\nGET\r\n:1\r\n", errs.ExpectedBulkString},
+		{"*1\r\n$3\r\nGETX\r\n", errs.InvalidBulkLength},
+		{"*1\r\n$2000\r\n", errs.InvalidBulkLength},
+		{"*x\r\n", errs.InvalidMultiBulkLength},
+	}
+	for _, c := range cases {
+		reader := resp.NewReader(strings.NewReader(c.input), 1024)
+		_, err := reader.ReadMultiBulk()
+		if err != c.err {
+			t.Errorf("Expected err for %q to be %v, got %v", c.input, c.err, err)
+		}
+		if !resp.IsProtocolError(err) {
+			t.Errorf("Expected %v to be a protocol error", err)
+		}
+	}
+}
+
+func TestReadInline(t *testing.T) {
+	reader := resp.NewReader(strings.NewReader("GET test\r\nKEYS *\n"), 1024)
+	for _, exp := range []string{"GET test", "KEYS *"} {
+		line, err := reader.ReadInline()
+		if err != nil {
+			t.Errorf("Expected err to be nil, got %v", err)
+		}
+		if line != exp {
+			t.Errorf("Expected line to be %s, got %s", exp, line)
+		}
+	}
+}
+
+func TestHasCommand(t *testing.T) {
+	input := "*2\r\n$3\r\nGET\r\n$1\r\na\r\nGET b\r\n*2\r\n$3\r\nGET\r\n$1"
+	reader := resp.NewReader(strings.NewReader(input), 1024)
+	reader.IsMultiBulk()
+	if !reader.HasCommand() {
+		t.Errorf("Expected a whole command to be buffered")
+	}
+	reader.ReadMultiBulk()
+	if !reader.HasCommand() {
+		t.Errorf("Expected the inline command to be buffered")
+	}
+	reader.ReadInline()
+	// the last command is cut in the middle of a bulk string
+	if reader.HasCommand() {
+		t.Errorf("Expected no whole command to be buffered")
+	}
+}
+
+func TestAppendValue(t *testing.T) {
+	cases := []struct {
+		value any
+		exp   string
+	}{
+		{"OK", "+OK\r\n"},
+		{1, ":1\r\n"},
+		{nil, "$-1\r\n"},
+		{[]byte(nil), "$-1\r\n"},
+		{model.NullArray{}, "*-1\r\n"},
+		{[]byte("test123"), "$7\r\ntest123\r\n"},
+		{[]string{"a", "bc"}, "*2\r\n$1\r\na\r\n$2\r\nbc\r\n"},
+		{[]model.SortedSet{{Score: 1.5, Member: "a"}}, "*2\r\n$1\r\na\r\n$3\r\n1.5\r\n"},
+		{[]model.FieldValue{{Field: "f", Value: "v"}}, "*2\r\n$1\r\nf\r\n$1\r\nv\r\n"},
+		{errs.SyntaxError, "-ERR syntax error\r\n"},
+		{errs.WrongType, "-" + errs.WrongType.Error() + "\r\n"},
+	}
+	for _, c := range cases {
+		act := string(resp.AppendValue(nil, c.value, resp.RESP2))
+		if act != c.exp {
+			t.Errorf("Expected %v to be encoded as %q, got %q", c.value, c.exp, act)
+		}
+	}
+}
+
+func TestAppendValueRESP3(t *testing.T) {
+	cases := []struct {
+		value any
+		exp   string
+	}{
+		{nil, "_\r\n"},
+		{[]byte(nil), "_\r\n"},
+		{model.NullArray{}, "_\r\n"},
+		{2.5, ",2.5\r\n"},
+		{[]model.SortedSet{{Score: 1.5, Member: "a"}}, "*1\r\n*2\r\n$1\r\na\r\n,1.5\r\n"},
+		{[]model.FieldValue{{Field: "f", Value: "v"}}, "*1\r\n*2\r\n$1\r\nf\r\n$1\r\nv\r\n"},
+		{model.Map{{Key: "proto", Value: 3}}, "%1\r\n$5\r\nproto\r\n:3\r\n"},
+		{model.Set{"a"}, "~1\r\n+a\r\n"},
+	}
+	for _, c := range cases {
+		act := string(resp.AppendValue(nil, c.value, resp.RESP3))
+		if act != c.exp {
+			t.Errorf("Expected %v to be encoded as %q, got %q", c.value, c.exp, act)
+		}
+	}
+}
@@ -0,0 +1,391 @@
+package unittest
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/saurabhy27/redis-database/constants"
+	"github.com/saurabhy27/redis-database/datastore"
+	"github.com/saurabhy27/redis-database/model"
+	"github.com/saurabhy27/redis-database/processor"
+	"github.com/saurabhy27/redis-database/server"
+)
+
+// runServer starts a server on a free port that stops with the test and
+// returns its address once it accepts connections.
+func runServer(t *testing.T) string {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	addr, stopped := runServerContext(t, ctx)
+	t.Cleanup(func() {
+		cancel()
+		<-stopped
+	})
+	return addr
+}
+
+// runServerContext starts a server on a free port until ctx is cancelled. It
+// returns the address once the server accepts connections and a channel
+// closed when Start returned.
+func runServerContext(t *testing.T, ctx context.Context) (string, <-chan struct{}) {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Expected err to be nil, got %v", err)
+	}
+	port := listener.Addr().(*net.TCPAddr).Port
+	listener.Close()
+	args := server.ServerArgs{Port: port, MaxBulkLen: constants.ProtoMaxBulkLen, ShutdownTimeout: time.Second}
+	srv := server.New(args, &processor.RequestProcessor{DataStore: datastore.New()})
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		srv.Start(ctx)
+	}()
+	addr := fmt.Sprintf("127.0.0.1:%d", port)
+	for i := 0; i < 100; i++ {
+		if conn, err := net.Dial("tcp", addr); err == nil {
+			conn.Close()
+			return addr, stopped
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("Expected the server to listen on %s", addr)
+	return "", stopped
+}
+
+// roundTrip sends raw bytes and reads back exactly len(exp) bytes.
+func roundTrip(t *testing.T, conn net.Conn, reader *bufio.Reader, input string, exp string) {
+	t.Helper()
+	if _, err := conn.Write([]byte(input)); err != nil {
+		t.Fatalf("Expected err to be nil, got %v", err)
+	}
+	buf := make([]byte, len(exp))
+	if _, err := io.ReadFull(reader, buf); err != nil {
+		t.Fatalf("Expected err to be nil, got %v", err)
+	}
+	if string(buf) != exp {
+		t.Errorf("Expected reply to be %q, got %q", exp, buf)
+	}
+}
+
+func TestServerPipeline(t *testing.T) {
+	t.Parallel()
+	conn, err := net.Dial("tcp", runServer(t))
+	if err != nil {
+		t.Fatalf("Expected err to be nil, got %v", err)
+	}
+	defer conn.Close()
+	reader := bufio.NewReader(conn)
+	input := "*3\r\n$3\r\nSET\r\n$4\r\ntest\r\n$11\r\nhello world\r\n" +
+		"*2\r\n$3\r\nGET\r\n$4\r\ntest\r\n" +
+		"*2\r\n$3\r\nGET\r\n$7\r\nmissing\r\n"
+	roundTrip(t, conn, reader, input, "+OK\r\n$11\r\nhello world\r\n$-1\r\n")
+	roundTrip(t, conn, reader, "*2\r\n$5\r\nHELLO\r\n$1\r\n3\r\n*2\r\n$3\r\nGET\r\n$7\r\nmissing\r\n", "%7\r\n")
+	reader.ReadString('*') // skip the HELLO map up to the empty modules array
+	roundTrip(t, conn, reader, "", "0\r\n_\r\n")
+}
+
+func TestServerPipelinePartialCommand(t *testing.T) {
+	t.Parallel()
+	conn, err := net.Dial("tcp", runServer(t))
+	if err != nil {
+		t.Fatalf("Expected err to be nil, got %v", err)
+	}
+	defer conn.Close()
+	reader := bufio.NewReader(conn)
+	// the reply is sent without waiting for the rest of the next command
+	roundTrip(t, conn, reader, "*2\r\n$3\r\nGET\r\n$4\r\ntest\r\n*2\r\n$3\r\nGE", "$-1\r\n")
+	roundTrip(t, conn, reader, "T\r\n$4\r\ntest\r\n", "$-1\r\n")
+}
+
+func TestServerInline(t *testing.T) {
+	t.Parallel()
+	conn, err := net.Dial("tcp", runServer(t))
+	if err != nil {
+		t.Fatalf("Expected err to be nil, got %v", err)
+	}
+	defer conn.Close()
+	reader := bufio.NewReader(conn)
+	roundTrip(t, conn, reader, "SET greeting \"hello world\"\r\nGET greeting\r\n", "OK\nhello world\nredis> ")
+	roundTrip(t, conn, reader, "HSET h f v\r\nHRANDFIELD h 2 WITHVALUES\r\n", "1\nf\nv\nredis> ")
+}
+
+func TestServerShutdownContext(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithCancel(context.Background())
+	addr, stopped := runServerContext(t, ctx)
+	conn, err := net.Dial("tcp", addr)
+	if err != nil {
+		t.Fatalf("Expected err to be nil, got %v", err)
+	}
+	defer conn.Close()
+	reader := bufio.NewReader(conn)
+	roundTrip(t, conn, reader, "GET test\n", "(nil)\nredis> ")
+	// the idle connection doesn't hold up the shutdown
+	cancel()
+	select {
+	case <-stopped:
+	case <-time.After(time.Second):
+		t.Fatalf("Expected Start to return")
+	}
+	if _, err := reader.ReadByte(); err == nil {
+		t.Errorf("Expected the connection to be closed")
+	}
+	if _, err := net.Dial("tcp", addr); err == nil {
+		t.Errorf("Expected the server to stop listening")
+	}
+}
+
+func TestServerShutdownCommand(t *testing.T) {
+	t.Parallel()
+	addr, stopped := runServerContext(t, context.Background())
+	conn, err := net.Dial("tcp", addr)
+	if err != nil {
+		t.Fatalf("Expected err to be nil, got %v", err)
+	}
+	defer conn.Close()
+	conn.Write([]byte("*2\r\n$8\r\nSHUTDOWN\r\n$6\r\nNOSAVE\r\n"))
+	reply, err := bufio.NewReader(conn).ReadString('\n')
+	if err == nil || strings.TrimSpace(reply) != "" {
+		t.Errorf("Expected the connection to be closed without a reply, got %q", reply)
+	}
+	select {
+	case <-stopped:
+	case <-time.After(time.Second):
+		t.Errorf("Expected Start to return")
+	}
+}
+
+// startServer runs an isolated server on a free port.
+func startServer(t *testing.T) *server.Server {
+	t.Helper()
+	srv := server.New(server.ServerArgs{Port: 0}, &processor.RequestProcessor{DataStore: datastore.New()})
+	if err := srv.Listen(); err != nil {
+		t.Fatalf("Expected err to be nil, got %v", err)
+	}
+	t.Cleanup(func() { srv.Close() })
+	return srv
+}
+
+func TestServerListenAddr(t *testing.T) {
+	t.Parallel()
+	srv := server.New(server.ServerArgs{Port: 0}, &processor.RequestProcessor{DataStore: datastore.New()})
+	if srv.Addr() != nil {
+		t.Errorf("Expected no address before Listen, got %v", srv.Addr())
+	}
+	if err := srv.Listen(); err != nil {
+		t.Fatalf("Expected err to be nil, got %v", err)
+	}
+	defer srv.Close()
+	if port := srv.Addr().(*net.TCPAddr).Port; port == 0 {
+		t.Errorf("Expected a free port to be bound, got %d", port)
+	}
+	conn, err := net.Dial("tcp", srv.Addr().String())
+	if err != nil {
+		t.Fatalf("Expected err to be nil, got %v", err)
+	}
+	defer conn.Close()
+	roundTrip(t, conn, bufio.NewReader(conn), "*2\r\n$3\r\nGET\r\n$4\r\ntest\r\n", "$-1\r\n")
+}
+
+func TestServerClose(t *testing.T) {
+	t.Parallel()
+	srv := startServer(t)
+	conn, err := net.Dial("tcp", srv.Addr().String())
+	if err != nil {
+		t.Fatalf("Expected err to be nil, got %v", err)
+	}
+	defer conn.Close()
+	reader := bufio.NewReader(conn)
+	roundTrip(t, conn, reader, "GET test\n", "(nil)\nredis> ")
+	// the idle connection doesn't hold up the shutdown
+	srv.Close()
+	if _, err := reader.ReadByte(); err == nil {
+		t.Errorf("Expected the connection to be closed")
+	}
+	if _, err := net.Dial("tcp", srv.Addr().String()); err == nil {
+		t.Errorf("Expected the server to stop listening")
+	}
+}
+
+func TestServerCloseWaitsForCommand(t *testing.T) {
+	t.Parallel()
+	slow := &slowProcessor{delay: 200 * time.Millisecond}
+	srv := server.New(server.ServerArgs{Port: 0}, slow)
+	if err := srv.Listen(); err != nil {
+		t.Fatalf("Expected err to be nil, got %v", err)
+	}
+	conn, err := net.Dial("tcp", srv.Addr().String())
+	if err != nil {
+		t.Fatalf("Expected err to be nil, got %v", err)
+	}
+	defer conn.Close()
+	conn.Write([]byte("*2\r\n$3\r\nGET\r\n$4\r\ntest\r\n"))
+	time.Sleep(50 * time.Millisecond)
+	srv.Close()
+	if !slow.finished.Load() {
+		t.Errorf("Expected the running command to finish before Close returned")
+	}
+}
+
+func TestServerBlockingPop(t *testing.T) {
+	t.Parallel()
+	srv := startServer(t)
+	dial := func() (net.Conn, *bufio.Reader) {
+		conn, err := net.Dial("tcp", srv.Addr().String())
+		if err != nil {
+			t.Fatalf("Expected err to be nil, got %v", err)
+		}
+		t.Cleanup(func() { conn.Close() })
+		return conn, bufio.NewReader(conn)
+	}
+	waiting, waitingReader := dial()
+	gone, _ := dial()
+	pusher, pusherReader := dial()
+
+	// a client that disconnects while blocked must not swallow a member
+	gone.Write([]byte("*3\r\n$8\r\nBZPOPMIN\r\n$1\r\nq\r\n$1\r\n0\r\n"))
+	time.Sleep(50 * time.Millisecond)
+	gone.Close()
+	time.Sleep(50 * time.Millisecond)
+	waiting.Write([]byte("*3\r\n$8\r\nBZPOPMIN\r\n$1\r\nq\r\n$1\r\n0\r\n"))
+	time.Sleep(50 * time.Millisecond)
+	roundTrip(t, pusher, pusherReader, "*4\r\n$4\r\nZADD\r\n$1\r\nq\r\n$1\r\n1\r\n$1\r\na\r\n", ":1\r\n")
+	roundTrip(t, waiting, waitingReader, "", "*3\r\n$1\r\nq\r\n$1\r\na\r\n$1\r\n1\r\n")
+	// the connection keeps working after a timeout
+	roundTrip(t, waiting, waitingReader, "*3\r\n$8\r\nBZPOPMAX\r\n$1\r\nq\r\n$4\r\n0.05\r\n", "*-1\r\n")
+	roundTrip(t, waiting, waitingReader, "*2\r\n$5\r\nZCARD\r\n$1\r\nq\r\n", ":0\r\n")
+}
+
+func TestServerBlockingPopPipelined(t *testing.T) {
+	t.Parallel()
+	srv := startServer(t)
+	gone, err := net.Dial("tcp", srv.Addr().String())
+	if err != nil {
+		t.Fatalf("Expected err to be nil, got %v", err)
+	}
+	conn, err := net.Dial("tcp", srv.Addr().String())
+	if err != nil {
+		t.Fatalf("Expected err to be nil, got %v", err)
+	}
+	defer conn.Close()
+	reader := bufio.NewReader(conn)
+
+	// the disconnect is noticed even with a command pipelined behind BZPOPMIN
+	gone.Write([]byte("*3\r\n$8\r\nBZPOPMIN\r\n$1\r\nq\r\n$1\r\n0\r\n*2\r\n$5\r\nZCARD\r\n$1\r\nq\r\n"))
+	time.Sleep(50 * time.Millisecond)
+	gone.Close()
+	time.Sleep(50 * time.Millisecond)
+	roundTrip(t, conn, reader, "*4\r\n$4\r\nZADD\r\n$1\r\nq\r\n$1\r\n1\r\n$1\r\na\r\n", ":1\r\n")
+	roundTrip(t, conn, reader, "*2\r\n$5\r\nZCARD\r\n$1\r\nq\r\n", ":1\r\n")
+}
+
+func TestServerBlockingListPipelined(t *testing.T) {
+	t.Parallel()
+	srv := startServer(t)
+	conn, err := net.Dial("tcp", srv.Addr().String())
+	if err != nil {
+		t.Fatalf("Expected err to be nil, got %v", err)
+	}
+	defer conn.Close()
+	reader := bufio.NewReader(conn)
+	for _, blocking := range []string{
+		"*3\r\n$5\r\nBLPOP\r\n$3\r\nsrc\r\n$1\r\n0\r\n",
+		"*6\r\n$6\r\nBLMOVE\r\n$3\r\nsrc\r\n$3\r\ndst\r\n$4\r\nLEFT\r\n$5\r\nRIGHT\r\n$1\r\n0\r\n",
+	} {
+		gone, err := net.Dial("tcp", srv.Addr().String())
+		if err != nil {
+			t.Fatalf("Expected err to be nil, got %v", err)
+		}
+		gone.Write([]byte(blocking + "*2\r\n$4\r\nLLEN\r\n$3\r\nsrc\r\n"))
+		time.Sleep(50 * time.Millisecond)
+		gone.Close()
+		time.Sleep(50 * time.Millisecond)
+		// the element stays in the source list, nothing is moved for the
+		// client that went away
+		roundTrip(t, conn, reader, "*3\r\n$5\r\nRPUSH\r\n$3\r\nsrc\r\n$1\r\na\r\n", ":1\r\n")
+		roundTrip(t, conn, reader, "*2\r\n$4\r\nLLEN\r\n$3\r\ndst\r\n", ":0\r\n")
+		roundTrip(t, conn, reader, "*2\r\n$4\r\nLPOP\r\n$3\r\nsrc\r\n", "$1\r\na\r\n")
+	}
+}
+
+func TestServerCloseWhileBlocked(t *testing.T) {
+	t.Parallel()
+	srv := startServer(t)
+	conn, err := net.Dial("tcp", srv.Addr().String())
+	if err != nil {
+		t.Fatalf("Expected err to be nil, got %v", err)
+	}
+	defer conn.Close()
+	conn.Write([]byte("*3\r\n$8\r\nBZPOPMIN\r\n$1\r\nq\r\n$1\r\n0\r\n"))
+	time.Sleep(50 * time.Millisecond)
+	start := time.Now()
+	srv.Close()
+	if time.Since(start) > time.Second {
+		t.Errorf("Expected Close not to wait for the blocked client, took %v", time.Since(start))
+	}
+}
+
+// slowProcessor answers every command after a delay, so it is still running
+// when the server shuts down.
+type slowProcessor struct {
+	delay    time.Duration
+	finished atomic.Bool
+}
+
+func (p *slowProcessor) Process(request model.Request) (model.Responce, error) {
+	time.Sleep(p.delay)
+	p.finished.Store(true)
+	return model.Responce{Success: true, Value: "OK"}, nil
+}
+
+func TestServerShutdownTimeout(t *testing.T) {
+	t.Parallel()
+	// a negative timeout disconnects the running command right away, Close
+	// still waits for it to return
+	slow := &slowProcessor{delay: 300 * time.Millisecond}
+	srv := server.New(server.ServerArgs{Port: 0, ShutdownTimeout: -1}, slow)
+	if err := srv.Listen(); err != nil {
+		t.Fatalf("Expected err to be nil, got %v", err)
+	}
+	conn, err := net.Dial("tcp", srv.Addr().String())
+	if err != nil {
+		t.Fatalf("Expected err to be nil, got %v", err)
+	}
+	defer conn.Close()
+	conn.Write([]byte("*2\r\n$3\r\nGET\r\n$4\r\ntest\r\n"))
+	time.Sleep(50 * time.Millisecond)
+	srv.Close()
+	if !slow.finished.Load() {
+		t.Errorf("Expected the running command to finish before Close returned")
+	}
+	if reply, err := bufio.NewReader(conn).ReadString('\n'); err == nil {
+		t.Errorf("Expected the connection to be closed without a reply, got %q", reply)
+	}
+
+	// a zero timeout picks the default, long enough for the command
+	slow = &slowProcessor{delay: 100 * time.Millisecond}
+	srv = server.New(server.ServerArgs{Port: 0}, slow)
+	if err := srv.Listen(); err != nil {
+		t.Fatalf("Expected err to be nil, got %v", err)
+	}
+	conn, err = net.Dial("tcp", srv.Addr().String())
+	if err != nil {
+		t.Fatalf("Expected err to be nil, got %v", err)
+	}
+	defer conn.Close()
+	conn.Write([]byte("*2\r\n$3\r\nGET\r\n$4\r\ntest\r\n"))
+	time.Sleep(50 * time.Millisecond)
+	srv.Close()
+	if reply, _ := bufio.NewReader(conn).ReadString('\n'); reply != "+OK\r\n" {
+		t.Errorf("Expected the running command to finish, got %q", reply)
+	}
+}
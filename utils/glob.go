@@ -0,0 +1,84 @@
+package utils
+
+// GlobMatch reports whether the whole of str matches the redis glob pattern:
+// * matches any sequence, ? any single character, [abc] [^a] and [a-z] a
+// character class and a backslash escapes the character following it.
+func GlobMatch(pattern, str string) bool {
+	p, s := 0, 0
+	// position of the last * seen and of the string when it was reached, a
+	// failed match retries from there with the * consuming one more byte
+	starP, starS := -1, 0
+	for s < len(str) {
+		if p < len(pattern) && pattern[p] == '*' {
+			starP, starS = p, s
+			p++
+			continue
+		}
+		if p < len(pattern) {
+			if width, ok := matchOne(pattern[p:], str[s]); ok {
+				p += width
+				s++
+				continue
+			}
+		}
+		if starP < 0 {
+			return false
+		}
+		starS++
+		p, s = starP+1, starS
+	}
+	for p < len(pattern) && pattern[p] == '*' {
+		p++
+	}
+	return p == len(pattern)
+}
+
+// matchOne matches c against the first token of pattern, which is not a *.
+// It returns the width of the token and whether c matched it.
+func matchOne(pattern string, c byte) (int, bool) {
+	switch pattern[0] {
+	case '?':
+		return 1, true
+	case '\\':
+		if len(pattern) > 1 {
+			return 2, pattern[1] == c
+		}
+		return 1, c == '\\'
+	case '[':
+		return matchClass(pattern, c)
+	default:
+		return 1, pattern[0] == c
+	}
+}
+
+// matchClass matches c against the character class pattern starts with. Like
+// redis, a class missing its closing bracket runs to the end of the pattern.
+func matchClass(pattern string, c byte) (int, bool) {
+	i := 1
+	negate := i < len(pattern) && pattern[i] == '^'
+	if negate {
+		i++
+	}
+	match := false
+	for ; i < len(pattern) && pattern[i] != ']'; i++ {
+		switch {
+		case pattern[i] == '\\' && i+1 < len(pattern):
+			i++
+			match = match || pattern[i] == c
+		case i+2 < len(pattern) && pattern[i+1] == '-':
+			start, end := pattern[i], pattern[i+2]
+			if start > end {
+				start, end = end, start
+			}
+			match = match || (c >= start && c <= end)
+			i += 2
+		default:
+			match = match || pattern[i] == c
+		}
+	}
+	if i < len(pattern) {
+		// skip the closing bracket
+		i++
+	}
+	return i, match != negate
+}
@@ -1,6 +1,10 @@
 package utils
 
-import "os"
+import (
+	"math"
+	"os"
+	"strconv"
+)
 
 func GetEnv(key, fallback string) string {
 	value := os.Getenv(key)
@@ -19,15 +23,34 @@ func Contains(s []string, e string) bool {
 	return false
 }
 
+// FormatArrayStartNEndIdx turns the start and stop indexes of a range over
+// n items into positive ones clamped to the items, negative indexes count
+// from the end like in redis. The range is empty when start > stop.
 func FormatArrayStartNEndIdx(start, stop, n int) (int, int) {
 	if start < 0 {
-		start = 0
+		start = n + start
 	}
 	if stop < 0 {
 		stop = n + stop
 	}
+	if start < 0 {
+		start = 0
+	}
 	if stop >= n {
 		stop = n - 1
 	}
 	return start, stop
 }
+
+// FormatFloat formats a score the way redis prints doubles.
+func FormatFloat(f float64) string {
+	switch {
+	case math.IsInf(f, 1):
+		return "inf"
+	case math.IsInf(f, -1):
+		return "-inf"
+	case f == 0 || (math.Abs(f) >= 1e-4 && math.Abs(f) < 1e17):
+		return strconv.FormatFloat(f, 'f', -1, 64)
+	}
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}